@@ -1,27 +1,42 @@
 package cli
 
 import (
+	"os"
 	"strings"
 
 	"github.com/codegangsta/cli"
-	kube "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/labels"
 
 	"rsprd.com/spread/pkg/data"
 	"rsprd.com/spread/pkg/deploy"
+	"rsprd.com/spread/pkg/input/dir"
+	pb "rsprd.com/spread/pkg/spreadproto"
 )
 
 // Add sets up a Spread repository for versioning.
 func (s SpreadCli) Add() *cli.Command {
 	return &cli.Command{
 		Name:        "add",
-		Usage:       "spread add <path>",
-		Description: "Stage objects to the index",
+		Usage:       "spread add <kind/name> | <kind> | --all | -f <file|dir>",
+		Description: "Stage objects to the index from a Kubernetes cluster or from manifest files",
 		Flags: []cli.Flag{
 			cli.StringFlag{
-				Name:  "namespace",
+				Name:  "f",
+				Usage: "file or directory of manifests to add instead of retrieving from a cluster, use '-' for stdin",
+			},
+			cli.StringFlag{
+				Name:  "namespace, n",
 				Value: "default",
 				Usage: "namespace to look for objects",
 			},
+			cli.BoolFlag{
+				Name:  "all",
+				Usage: "add every object in the namespace",
+			},
+			cli.StringFlag{
+				Name:  "selector, l",
+				Usage: "only add objects matching the label selector",
+			},
 			cli.StringFlag{
 				Name:  "context",
 				Value: "",
@@ -35,72 +50,192 @@ func (s SpreadCli) Add() *cli.Command {
 				Name:  "clean",
 				Usage: "Removes fields that are known to cause issues with reproducibility",
 			},
+			cli.StringSliceFlag{
+				Name:  "no-clean-kind",
+				Usage: "with --clean, skip the cleaning specific to a kind (e.g. service), may be given multiple times",
+			},
 		},
 		Action: func(c *cli.Context) {
-			// Download specified object from Kubernetes cluster
+			namespace := c.String("namespace")
+			cleaner := s.cleaner(c.Bool("clean"), c.StringSlice("no-clean-kind"))
+			if file := c.String("f"); len(file) != 0 {
+				s.addFromFiles(file, namespace, cleaner)
+				return
+			}
+
+			// Download specified objects from Kubernetes cluster
 			// example: spread add rc/mattermost
 			resource := c.Args().First()
-			if len(resource) == 0 {
+			all, selectorStr := c.Bool("all"), c.String("selector")
+			if len(resource) == 0 && !all && len(selectorStr) == 0 {
 				s.fatalf("A resource to be added must be specified")
 			}
 
+			var selector labels.Selector
+			if len(selectorStr) != 0 {
+				var err error
+				if selector, err = labels.Parse(selectorStr); err != nil {
+					s.fatalf("Invalid selector: %v", err)
+				}
+			}
+
 			context := c.String("context")
 			cluster, err := deploy.NewKubeClusterFromContext(context)
 			if err != nil {
 				s.fatalf("Failed to connect to Kubernetes cluster: %v", err)
 			}
 
-			// parse resource type and name
-			parts := strings.Split(resource, "/")
-			if len(parts) != 2 {
-				s.fatalf("Unrecognized resource format")
-			}
-
-			kind, name := parts[0], parts[1]
-			namespace := c.String("namespace")
 			export := !c.Bool("no-export")
 
-			kubeObj, err := cluster.Get(kind, namespace, name, export)
-			if err != nil {
-				s.fatalf("Could not get object from cluster: %v", err)
-			}
-
-			if c.Bool("clean") {
-				err = cleanObj(kubeObj)
+			var objects []deploy.KubeObject
+			switch parts := strings.Split(resource, "/"); {
+			case len(resource) == 0:
+				// no resource means the entire namespace
+				for _, kind := range deploy.NamespacedResources {
+					listed, err := cluster.List(kind, namespace, selector, export)
+					if err != nil {
+						s.fatalf("Could not get objects from cluster: %v", err)
+					}
+					objects = append(objects, withoutControllerManaged(listed)...)
+				}
+			case len(parts) == 1:
+				objects, err = cluster.List(parts[0], namespace, selector, export)
+				if err != nil {
+					s.fatalf("Could not get objects from cluster: %v", err)
+				}
+			case len(parts) == 2 && !all:
+				kubeObj, err := cluster.Get(parts[0], namespace, parts[1], export)
 				if err != nil {
 					s.fatalf("Could not get object from cluster: %v", err)
 				}
+				objects = append(objects, kubeObj)
+			default:
+				s.fatalf("Unrecognized resource format")
 			}
 
-			// TODO(DG): Clean this up
-			gvk := kubeObj.GetObjectKind().GroupVersionKind()
-			gvk.Version = "v1"
-			kubeObj.GetObjectKind().SetGroupVersionKind(gvk)
-			kubeObj.GetObjectMeta().SetNamespace(namespace)
-
-			path, err := deploy.ObjectPath(kubeObj)
-			if err != nil {
-				s.fatalf("Failed to determine path to save object: %v", err)
+			if len(objects) == 0 {
+				s.fatalf("No matching objects found in namespace '%s'", namespace)
 			}
 
-			obj, err := data.CreateDocument(kubeObj.GetObjectMeta().GetName(), path, kubeObj)
-			if err != nil {
-				s.fatalf("failed to encode document: %v", err)
+			if cleaner != nil {
+				objects = s.cleanObjects(cleaner, objects)
 			}
 
-			proj := s.projectOrDie()
-			err = proj.AddDocumentToIndex(obj)
-			if err != nil {
-				s.fatalf("Failed to add object to Git index: %v", err)
+			for _, kubeObj := range objects {
+				// TODO(DG): Clean this up
+				gvk := kubeObj.GetObjectKind().GroupVersionKind()
+				gvk.Version = "v1"
+				kubeObj.GetObjectKind().SetGroupVersionKind(gvk)
+				kubeObj.GetObjectMeta().SetNamespace(namespace)
 			}
+
+			s.stageObjects(objects)
 		},
 	}
 }
 
-func cleanObj(obj deploy.KubeObject) error {
-	switch typedObj := obj.(type) {
-	case *kube.Service:
-		typedObj.Spec.ClusterIP = ""
+// addFromFiles stages the objects in the manifests at path. Objects without a namespace are placed in namespace. If
+// cleaner isn't nil, objects are cleaned with it.
+func (s SpreadCli) addFromFiles(path, namespace string, cleaner *deploy.Cleaner) {
+	objects, err := s.manifestObjects(path)
+	if err != nil {
+		s.fatalf("Could not read objects from '%s': %v", path, err)
+	} else if len(objects) == 0 {
+		s.fatalf("No objects found in '%s'", path)
+	}
+
+	if cleaner != nil {
+		objects = s.cleanObjects(cleaner, objects)
+	}
+
+	for _, kubeObj := range objects {
+		if len(kubeObj.GetObjectMeta().GetNamespace()) == 0 {
+			kubeObj.GetObjectMeta().SetNamespace(namespace)
+		}
+	}
+
+	s.stageObjects(objects)
+}
+
+// manifestObjects returns the objects at path. Directories following the Redspread convention are built into an
+// entity, otherwise path is read as plain manifests.
+func (s SpreadCli) manifestObjects(path string) ([]deploy.KubeObject, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		if dep, err := s.fileDeploy(path); err == nil && dep.Len() > 0 {
+			return dep.Objects(), nil
+		}
+	}
+	return dir.ObjectsFromPath(path, false)
+}
+
+// stageObjects encodes objects as documents and adds them to the index with a single write.
+func (s SpreadCli) stageObjects(objects []deploy.KubeObject) {
+	docs := make([]*pb.Document, 0, len(objects))
+	for _, kubeObj := range objects {
+		path, err := deploy.ObjectPath(kubeObj)
+		if err != nil {
+			s.fatalf("Failed to determine path to save object: %v", err)
+		}
+
+		doc, err := data.CreateDocument(kubeObj.GetObjectMeta().GetName(), path, kubeObj)
+		if err != nil {
+			s.fatalf("failed to encode document: %v", err)
+		}
+		docs = append(docs, doc)
+	}
+
+	proj := s.projectOrDie()
+	if err := proj.AddDocumentsToIndex(docs...); err != nil {
+		s.fatalf("Failed to add objects to Git index: %v", err)
+	}
+
+	for _, doc := range docs {
+		s.printf("add '%s'", doc.GetInfo().Path)
+	}
+}
+
+// withoutControllerManaged returns objects excluding those created by controllers.
+func withoutControllerManaged(objects []deploy.KubeObject) []deploy.KubeObject {
+	var filtered []deploy.KubeObject
+	for _, obj := range objects {
+		if !deploy.ControllerManaged(obj) {
+			filtered = append(filtered, obj)
+		}
+	}
+	return filtered
+}
+
+// cleaner returns the Cleaner used to clean objects, or nil if clean is false. Cleaning specific to the kinds in
+// skipKinds isn't performed.
+func (s SpreadCli) cleaner(clean bool, skipKinds []string) *deploy.Cleaner {
+	if !clean {
+		if len(skipKinds) != 0 {
+			s.fatalf("--no-clean-kind can only be used with --clean")
+		}
+		return nil
+	}
+
+	cleaner := deploy.NewCleaner()
+	for _, kind := range skipKinds {
+		if _, ok := cleaner.Kinds[strings.ToLower(kind)]; !ok {
+			s.fatalf("There is no cleaning specific to kind '%s'", kind)
+		}
+		cleaner.Skip(kind)
+	}
+	return cleaner
+}
+
+// cleanObjects removes cluster managed information from objects using cleaner. Objects generated by the cluster are
+// excluded.
+func (s SpreadCli) cleanObjects(cleaner *deploy.Cleaner, objects []deploy.KubeObject) []deploy.KubeObject {
+	var cleaned []deploy.KubeObject
+	for _, kubeObj := range objects {
+		keep, err := cleaner.Clean(kubeObj)
+		if err != nil {
+			s.fatalf("Could not clean object: %v", err)
+		} else if keep {
+			cleaned = append(cleaned, kubeObj)
+		}
 	}
-	return nil
+	return cleaned
 }
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/codegangsta/cli"
+
+	"rsprd.com/spread/pkg/data"
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+// argFlags are the flags of commands that satisfy parameters.
+var argFlags = []cli.Flag{
+	cli.StringSliceFlag{
+		Name:  "arg",
+		Usage: "argument for a parameter in the form name=value, may be given multiple times",
+	},
+	cli.StringFlag{
+		Name:  "args-file",
+		Usage: "JSON or YAML file with arguments keyed by parameter name",
+	},
+	cli.BoolFlag{
+		Name:  "no-prompt",
+		Usage: "fail instead of prompting for parameters without arguments",
+	},
+}
+
+// argOptions control how arguments for parameters are gathered.
+type argOptions struct {
+	// args are arguments in the form name=value, they take precedence over all other sources.
+	args []string
+	// file is the path of a JSON or YAML file of arguments.
+	file string
+	// noPrompt disables prompting. Defaults are used for parameters without arguments.
+	noPrompt bool
+	// sources compute arguments for parameters with a source. They are set by commands rather than flags.
+	sources data.Sources
+}
+
+// argOptionsFromContext returns the argOptions set by argFlags.
+func argOptionsFromContext(c *cli.Context) argOptions {
+	return argOptions{
+		args:     c.StringSlice("arg"),
+		file:     c.String("args-file"),
+		noPrompt: c.Bool("no-prompt"),
+	}
+}
+
+// applyArgs satisfies the parameters in docs. Arguments are taken from flags, SPREAD_ARG_<NAME> environment variables,
+// and the arguments file, in that order of precedence. Parameters with a source that weren't given an argument are
+// computed. Remaining parameters are prompted for on prompts unless prompting is disabled, in which case an error is
+// returned if any of them are required. Encrypted Secret documents are decrypted first so arguments apply to, and
+// sources see, their plaintext.
+func (s *SpreadCli) applyArgs(docs map[string]*pb.Document, opts argOptions, prompts io.Writer) error {
+	if err := s.decryptSecrets(docs); err != nil {
+		return err
+	}
+
+	fields := data.ParameterFields(docs)
+	names := data.ParameterNames(fields)
+
+	args := data.Arguments{}
+	if len(opts.file) != 0 {
+		fileArgs, err := data.ArgumentsFromFile(opts.file)
+		if err != nil {
+			return err
+		}
+		args.Merge(fileArgs)
+	}
+
+	envArgs, err := data.ArgumentsFromEnv(names, os.LookupEnv)
+	if err != nil {
+		return err
+	}
+	args.Merge(envArgs)
+
+	flagArgs := data.Arguments{}
+	for _, arg := range opts.args {
+		if err = flagArgs.ParseArgumentFlag(arg); err != nil {
+			return err
+		}
+	}
+
+	for name := range flagArgs {
+		if _, exists := fields[name]; !exists {
+			return fmt.Errorf("no parameter named '%s'", name)
+		}
+	}
+	args.Merge(flagArgs)
+
+	computed, err := opts.sources.Arguments(fields, docs, args)
+	if err != nil {
+		return err
+	}
+	args.Merge(computed)
+
+	var missing []string
+	for _, name := range names {
+		shared := fields[name]
+		if err = data.CheckSharedParameter(shared); err != nil {
+			return err
+		}
+
+		if given, ok := args[name]; ok {
+			err = data.ApplySharedArguments(shared, given...)
+		} else if !opts.noPrompt {
+			err = s.promptArgs(shared, prompts)
+		} else if hasDefaults(shared) {
+			err = data.ApplySharedArguments(shared)
+		} else {
+			missing = append(missing, name)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(missing) != 0 {
+		return fmt.Errorf("%v: %s", ErrMissingArguments, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// promptArgs prompts for the parameter shared by fields. Input for secret parameters isn't echoed.
+func (s *SpreadCli) promptArgs(fields []*pb.Field, prompts io.Writer) error {
+	if data.IsSecretParameter(fields[0].GetParam()) {
+		if restore, err := s.disableEcho(); err == nil {
+			defer fmt.Fprintln(prompts)
+			defer restore()
+		}
+	}
+	return data.InteractiveSharedArgs(s.in, prompts, fields, false)
+}
+
+// hasDefaults returns true if the parameter of every field has a default.
+func hasDefaults(fields []*pb.Field) bool {
+	for _, field := range fields {
+		if field.GetParam().GetDefault() == nil {
+			return false
+		}
+	}
+	return true
+}
@@ -60,15 +60,26 @@ func (c SpreadCli) project() (*project.Project, error) {
 	if err != nil {
 		return nil, fmt.Errorf("Error opening project: %v", err)
 	}
+
+	if err = c.configureProject(proj); err != nil {
+		return nil, err
+	}
 	return proj, nil
 }
 
 func (c SpreadCli) globalProject() (*project.Project, error) {
 	proj, err := project.Global()
 	if err != nil {
-		if strings.HasSuffix(err.Error(), "no such file or directory") {
-			return project.InitGlobal()
+		if !strings.HasSuffix(err.Error(), "no such file or directory") {
+			return nil, err
 		}
+
+		if proj, err = project.InitGlobal(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = c.configureProject(proj); err != nil {
 		return nil, err
 	}
 	return proj, nil
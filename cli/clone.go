@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/codegangsta/cli"
+
+	"rsprd.com/spread/pkg/config"
+	"rsprd.com/spread/pkg/packages"
+	"rsprd.com/spread/pkg/project"
+)
+
+// Clone creates a project from an existing repository.
+func (s SpreadCli) Clone() *cli.Command {
+	return &cli.Command{
+		Name:        "clone",
+		Usage:       "spread clone <url|package> [dir]",
+		Description: "Create a Spread project from a remote repository. Package names are resolved using package discovery. If no directory is given, one is named after the repository.",
+		Action: func(c *cli.Context) {
+			source := c.Args().First()
+			if len(source) == 0 {
+				s.fatalf("a URL or package name must be specified")
+			}
+
+			url := source
+			if !isRepoURL(source) {
+				var err error
+				if url, err = s.discoverRepo(source); err != nil {
+					s.fatalf("Could not resolve package '%s': %v", source, err)
+				}
+			}
+
+			dir := c.Args().Get(1)
+			if len(dir) == 0 {
+				dir = repoDirName(source)
+			}
+
+			ctx, stop := s.interruptContext()
+			defer stop()
+
+			s.printf("Cloning %s into %s...", url, dir)
+			target := filepath.Join(dir, project.SpreadDirectory)
+			proj, err := project.Clone(ctx, url, target, s.configureProject)
+			if err != nil {
+				s.fatalf("Could not clone: %v", err)
+			}
+
+			s.printf("Created Spread repository in %s.", proj.Path)
+		},
+	}
+}
+
+// discoverRepo returns the repository URL of a package.
+func (s SpreadCli) discoverRepo(name string) (string, error) {
+	name, err := packages.ExpandPackageName(name)
+	if err != nil {
+		return "", err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return "", err
+	}
+
+	resolver, err := packages.NewResolver(cfg.Packages, nil)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := resolver.Discover(name)
+	if err != nil {
+		return "", err
+	}
+	return info.RepoURL, nil
+}
+
+// isRepoURL returns true if source is a Git URL or an existing local path rather than a package name.
+func isRepoURL(source string) bool {
+	if strings.Contains(source, "://") {
+		return true
+	}
+
+	// scp-like syntax, such as git@github.com:redspread/spread.git
+	if i := strings.Index(source, ":"); i > 0 && !strings.Contains(source[:i], "/") {
+		return true
+	}
+
+	_, err := os.Stat(source)
+	return err == nil
+}
+
+// repoDirName returns the directory name used for a clone of source.
+func repoDirName(source string) string {
+	source = strings.TrimRight(source, "/")
+	if i := strings.LastIndexAny(source, ":/"); i >= 0 {
+		source = source[i+1:]
+	}
+	return strings.TrimSuffix(path.Base(source), ".git")
+}
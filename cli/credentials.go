@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/pkg/term"
+
+	"rsprd.com/spread/pkg/config"
+	"rsprd.com/spread/pkg/credentials"
+	"rsprd.com/spread/pkg/hostkeys"
+	"rsprd.com/spread/pkg/project"
+	"rsprd.com/spread/pkg/secrets"
+)
+
+// configureProject sets up proj to use the credential providers, host verification, and Secret settings from the
+// user's configuration.
+func (c SpreadCli) configureProject(proj *project.Project) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	proj.Credentials = credentials.FromConfig(cfg.Credentials, c.promptPassphrase)
+	proj.HostKeys = hostkeys.FromConfig(cfg)
+	proj.PlaintextSecrets = cfg.PlaintextSecrets
+	proj.Identity = func() (*secrets.Identity, error) {
+		return c.identity(false)
+	}
+	if bar := c.progress(); bar != nil {
+		proj.Progress = bar
+	}
+	return nil
+}
+
+// promptPassphrase asks for the passphrase of the SSH key at path without echoing input.
+func (c SpreadCli) promptPassphrase(path string) (string, error) {
+	passphrase, err := c.promptHidden(fmt.Sprintf("Enter passphrase for key '%s': ", path))
+	if err != nil {
+		return "", fmt.Errorf("cannot prompt for passphrase of '%s': %v", path, err)
+	}
+	return passphrase, nil
+}
+
+// promptHidden writes prompt and reads a line of input without echoing it.
+func (c SpreadCli) promptHidden(prompt string) (string, error) {
+	restore, err := c.disableEcho()
+	if err != nil {
+		return "", err
+	}
+	defer restore()
+
+	fmt.Fprint(c.out, prompt)
+	line, err := bufio.NewReader(c.in).ReadString('\n')
+	fmt.Fprintln(c.out)
+	if err != nil {
+		return "", fmt.Errorf("could not read input: %v", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// disableEcho stops input from being echoed until restore is called. An error is returned if input is not a terminal.
+func (c SpreadCli) disableEcho() (restore func(), err error) {
+	fd, isTerminal := term.GetFdInfo(c.in)
+	if !isTerminal {
+		return nil, errors.New("input is not a terminal")
+	}
+
+	state, err := term.SaveState(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = term.DisableEcho(fd, state); err != nil {
+		return nil, err
+	}
+	return func() { term.RestoreTerminal(fd, state) }, nil
+}
@@ -8,21 +8,25 @@ import (
 	"rsprd.com/spread/pkg/deploy"
 	"rsprd.com/spread/pkg/entity"
 	"rsprd.com/spread/pkg/input/dir"
-	"rsprd.com/spread/pkg/packages"
+	"rsprd.com/spread/pkg/project"
 	pb "rsprd.com/spread/pkg/spreadproto"
 
 	"github.com/codegangsta/cli"
+	"golang.org/x/net/context"
 )
 
 // Deploy allows the creation of deploy.Deployments remotely
 func (s *SpreadCli) Deploy() *cli.Command {
 	return &cli.Command{
 		Name:        "deploy",
-		Usage:       "spread deploy [-s] PATH | COMMIT [kubectl context]",
+		Usage:       "spread deploy [-s] PATH | COMMIT | PACKAGE[@VERSION] [kubectl context]",
 		Description: "Deploys objects to a remote Kubernetes cluster.",
 		ArgsUsage:   "-s will deploy only if no other deployment found (otherwise fails)",
+		Flags:       append(append([]cli.Flag{}, packageFlags...), argFlags...),
 		Action: func(c *cli.Context) {
 			ref := c.Args().First()
+			context := c.Args().Get(1)
+			argOpts := argOptionsFromContext(c)
 			var dep *deploy.Deployment
 
 			proj, err := s.project()
@@ -35,28 +39,31 @@ func (s *SpreadCli) Deploy() *cli.Command {
 						s.fatalf("Error getting index: %v", err)
 					}
 
-					if err = s.promptForArgs(docs, false); err == nil {
-						dep, err = deploy.DeploymentFromDocMap(docs)
+					argOpts.sources = s.paramSources(proj, "HEAD", context)
+					if err = s.applyArgs(docs, argOpts, s.out); err == nil {
+						dep, err = projectDeployment(proj, docs)
 					}
 
 				} else {
 					if docs, err = proj.ResolveCommit(ref); err == nil {
-						if err = s.promptForArgs(docs, false); err == nil {
-							dep, err = deploy.DeploymentFromDocMap(docs)
+						argOpts.sources = s.paramSources(proj, ref, context)
+						if err = s.applyArgs(docs, argOpts, s.out); err == nil {
+							dep, err = projectDeployment(proj, docs)
 						}
 					} else {
-						dep, err = s.globalDeploy(ref)
+						argOpts.sources = s.paramSources(nil, "", context)
+						dep, err = s.interruptibleGlobalDeploy(ref, proj, packageOptionsFromContext(c), argOpts)
 					}
 				}
 			} else {
-				dep, err = s.globalDeploy(ref)
+				argOpts.sources = s.paramSources(nil, "", context)
+				dep, err = s.interruptibleGlobalDeploy(ref, nil, packageOptionsFromContext(c), argOpts)
 			}
 
 			if err != nil {
 				s.fatalf("Failed to assemble deployment: %v", err)
 			}
 
-			context := c.Args().Get(1)
 			cluster, err := deploy.NewKubeClusterFromContext(context)
 			if err != nil {
 				s.fatalf("Failed to deploy: %v", err)
@@ -108,69 +115,47 @@ func (s *SpreadCli) fileDeploy(srcDir string) (*deploy.Deployment, error) {
 	return dep, nil
 }
 
-func (s *SpreadCli) globalDeploy(ref string) (*deploy.Deployment, error) {
+// projectDeployment resolves the links in docs of proj and returns them as a Deployment.
+func projectDeployment(proj *project.Project, docs map[string]*pb.Document) (*deploy.Deployment, error) {
+	if err := resolveLinks(proj, docs); err != nil {
+		return nil, err
+	}
+	return deploy.DeploymentFromDocMap(docs)
+}
+
+// interruptibleGlobalDeploy calls globalDeploy with a context cancelled by Ctrl-C. The interrupt handler is removed
+// once packages have been retrieved, so the rest of the deploy can be interrupted normally.
+func (s *SpreadCli) interruptibleGlobalDeploy(ref string, local *project.Project, opts packageOptions,
+	argOpts argOptions) (*deploy.Deployment, error) {
+	ctx, stop := s.interruptContext()
+	defer stop()
+	return s.globalDeploy(ctx, ref, local, opts, argOpts)
+}
+
+// globalDeploy deploys ref as a local directory or a package. If local is not nil, packages are pinned using its
+// lock file. Parameters of packages are satisfied using argOpts.
+func (s *SpreadCli) globalDeploy(ctx context.Context, ref string, local *project.Project, opts packageOptions,
+	argOpts argOptions) (*deploy.Deployment, error) {
 	// check if reference is local file
 	dep, err := s.fileDeploy(ref)
 	if err != nil {
-		ref, err = packages.ExpandPackageName(ref)
-		if err == nil {
-			var info packages.PackageInfo
-			info, err = packages.DiscoverPackage(ref, true, false)
-			if err != nil {
-				s.fatalf("failed to retrieve package info: %v", err)
-			}
-
-			proj, err := s.globalProject()
-			if err != nil {
-				s.fatalf("error setting up global project: %v", err)
-			}
-
-			remote, err := proj.Remotes().Lookup(ref)
-			// if does not exist or has different URL, create new remote
-			if err != nil {
-				remote, err = proj.Remotes().Create(ref, info.RepoURL)
-				if err != nil {
-					return nil, fmt.Errorf("could not create remote: %v", err)
-				}
-			} else if remote.Url() != info.RepoURL {
-				s.printf("changing remote URL for %s, current: '%s' new: '%s'", ref, remote.Url(), info.RepoURL)
-				err = proj.Remotes().SetUrl(ref, info.RepoURL)
-				if err != nil {
-					return nil, fmt.Errorf("failed to change URL for %s: %v", ref, err)
-				}
-			}
-
-			s.printf("pulling repo from %s", info.RepoURL)
-			branch := fmt.Sprintf("%s/master", ref)
-			err = proj.Fetch(remote.Name(), "master")
-			if err != nil {
-				return nil, fmt.Errorf("failed to fetch '%s': %v", ref, err)
-			}
-
-			docs, err := proj.Branch(branch)
-			if err != nil {
-				return nil, err
-			}
-
-			if err = s.promptForArgs(docs, false); err != nil {
-				return nil, err
-			}
+		var docs map[string]*pb.Document
+		docs, err = s.packageDocs(ctx, ref, local, opts)
+		if err != nil {
+			return nil, err
+		}
 
-			return deploy.DeploymentFromDocMap(docs)
+		if err = s.applyArgs(docs, argOpts, s.out); err != nil {
+			return nil, err
 		}
-	}
-	return dep, err
-}
 
-func (s *SpreadCli) promptForArgs(docs map[string]*pb.Document, required bool) error {
-	paramFields := data.ParameterFields(docs)
-	for _, field := range paramFields {
-		err := data.InteractiveArgs(s.in, s.out, field, required)
-		if err != nil {
-			return err
+		// only links within the package can be followed
+		if err = data.ResolveLinks(docs, nil); err != nil {
+			return nil, err
 		}
+		return deploy.DeploymentFromDocMap(docs)
 	}
-	return nil
+	return dep, err
 }
 
 func objectOnlyDeploy(input *dir.FileInput) (*deploy.Deployment, error) {
@@ -204,4 +189,5 @@ func displayContext(name string) string {
 
 var (
 	ErrNothingDeployable = errors.New("there is nothing deployable")
+	ErrMissingArguments  = errors.New("no arguments given for required parameters")
 )
@@ -26,6 +26,11 @@ func (s SpreadCli) Diff() *cli.Command {
 				s.fatalf("Could not load Index: %v", err)
 			}
 
+			// compare the plaintext of Secrets with the cluster
+			if err = s.decryptSecrets(docs); err != nil {
+				s.fatalf("%v", err)
+			}
+
 			index, err := deploy.DeploymentFromDocMap(docs)
 			if err != nil {
 				s.fatalf("Failed to create Deployment from Documents: %v", err)
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"github.com/codegangsta/cli"
+
+	"rsprd.com/spread/pkg/deploy"
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+// Export writes the objects of a commit or the index as Kubernetes manifests.
+func (s SpreadCli) Export() *cli.Command {
+	return &cli.Command{
+		Name:        "export",
+		Usage:       "spread export [revision] -o <dir|->",
+		Description: "Render a commit or the index to plain Kubernetes manifests",
+		Flags: append([]cli.Flag{
+			cli.StringFlag{
+				Name:  "output, o",
+				Usage: "directory to write manifests to, use '-' to write a single stream to stdout",
+			},
+			cli.StringFlag{
+				Name:  "format",
+				Value: deploy.FormatYAML,
+				Usage: "encoding of manifests, either 'yaml' or 'json'",
+			},
+			cli.StringFlag{
+				Name:  "context",
+				Usage: "kubectl context used by parameters computed from a cluster",
+			},
+		}, argFlags...),
+		Action: func(c *cli.Context) {
+			output := c.String("output")
+			if len(output) == 0 {
+				s.fatalf("An output directory must be specified with -o")
+			}
+
+			format := c.String("format")
+			if format != deploy.FormatYAML && format != deploy.FormatJSON {
+				s.fatalf("Unknown format '%s', must be 'yaml' or 'json'", format)
+			}
+
+			proj := s.projectOrDie()
+
+			var docs map[string]*pb.Document
+			var err error
+			revision := c.Args().First()
+			if len(revision) == 0 {
+				revision = "HEAD"
+				docs, err = proj.Index()
+			} else {
+				docs, err = proj.ResolveCommit(revision)
+			}
+			if err != nil {
+				s.fatalf("Could not load documents: %v", err)
+			}
+
+			// keep prompts out of the manifest stream
+			prompts := s.out
+			if output == "-" {
+				prompts = s.err
+			}
+
+			argOpts := argOptionsFromContext(c)
+			argOpts.sources = s.paramSources(proj, revision, c.String("context"))
+			if err = s.applyArgs(docs, argOpts, prompts); err != nil {
+				s.fatalf("Could not apply arguments: %v", err)
+			}
+
+			if err = resolveLinks(proj, docs); err != nil {
+				s.fatalf("Could not export: %v", err)
+			}
+
+			dep, err := deploy.DeploymentFromDocMap(docs)
+			if err != nil {
+				s.fatalf("Failed to assemble deployment: %v", err)
+			}
+
+			if output == "-" {
+				if err = deploy.WriteManifestStream(s.out, dep, format); err != nil {
+					s.fatalf("Failed to export: %v", err)
+				}
+				return
+			}
+
+			written, err := deploy.WriteManifests(dep, output, format)
+			if err != nil {
+				s.fatalf("Failed to export: %v", err)
+			}
+			s.printf("Exported %d objects to '%s'.", len(written), output)
+		},
+	}
+}
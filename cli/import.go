@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/codegangsta/cli"
+
+	"rsprd.com/spread/pkg/data"
+	"rsprd.com/spread/pkg/deploy"
+	"rsprd.com/spread/pkg/input/dir"
+	"rsprd.com/spread/pkg/project"
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+// Import commits the manifests in a directory as the new state of the project.
+func (s SpreadCli) Import() *cli.Command {
+	return &cli.Command{
+		Name:        "import",
+		Usage:       "spread import <dir>",
+		Description: "Replace the contents of the project with the manifests in a directory and its subdirectories, then commit the result. Documents missing from the directory are removed.",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "namespace, n",
+				Value: "default",
+				Usage: "namespace of objects that don't specify one",
+			},
+			cli.StringFlag{
+				Name:  "m",
+				Usage: "message to store the commit with, one is generated if not given",
+			},
+		},
+		Action: func(c *cli.Context) {
+			srcDir := c.Args().First()
+			if len(srcDir) == 0 {
+				s.fatalf("A directory to import must be specified")
+			}
+
+			objects, err := dir.ObjectsFromPath(srcDir, true)
+			if err != nil {
+				s.fatalf("Could not read objects from '%s': %v", srcDir, err)
+			} else if len(objects) == 0 {
+				s.fatalf("No objects found in '%s'", srcDir)
+			}
+
+			namespace := c.String("namespace")
+			docs := make([]*pb.Document, 0, len(objects))
+			for _, kubeObj := range objects {
+				if len(kubeObj.GetObjectMeta().GetNamespace()) == 0 {
+					kubeObj.GetObjectMeta().SetNamespace(namespace)
+				}
+
+				path, err := deploy.ObjectPath(kubeObj)
+				if err != nil {
+					s.fatalf("Failed to determine path to save object: %v", err)
+				}
+
+				doc, err := data.CreateDocument(kubeObj.GetObjectMeta().GetName(), path, kubeObj)
+				if err != nil {
+					s.fatalf("failed to encode document: %v", err)
+				}
+				docs = append(docs, doc)
+			}
+
+			proj := s.projectOrDie()
+			stat, err := proj.ImportDocuments(docs...)
+			if err != nil {
+				s.fatalf("Could not import objects: %v", err)
+			} else if stat.Empty() {
+				s.printf("Nothing to import, '%s' matches HEAD.", srcDir)
+				return
+			}
+
+			msg := c.String("m")
+			if len(msg) == 0 {
+				msg = importMessage(srcDir, stat)
+			}
+
+			notImplemented := project.Person{
+				Name:  "not implemented",
+				Email: "not@implemented.com",
+				When:  time.Now(),
+			}
+
+			oid, err := proj.Commit("HEAD", notImplemented, notImplemented, msg)
+			if err != nil {
+				s.fatalf("Could not commit: %v", err)
+			}
+
+			for _, path := range stat.Added {
+				s.printf("add '%s'", path)
+			}
+			for _, path := range stat.Modified {
+				s.printf("modify '%s'", path)
+			}
+			for _, path := range stat.Removed {
+				s.printf("rm '%s'", path)
+			}
+			s.printf("New commit: [%s] %s", oid, msg)
+		},
+	}
+}
+
+// importMessage generates a commit message summarizing an import from srcDir.
+func importMessage(srcDir string, stat project.ImportStat) string {
+	return fmt.Sprintf("Import from %s: %d added, %d modified, %d removed", filepath.Base(filepath.Clean(srcDir)),
+		len(stat.Added), len(stat.Modified), len(stat.Removed))
+}
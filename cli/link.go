@@ -4,6 +4,8 @@ import (
 	"github.com/codegangsta/cli"
 
 	"rsprd.com/spread/pkg/data"
+	"rsprd.com/spread/pkg/project"
+	pb "rsprd.com/spread/pkg/spreadproto"
 )
 
 // Link allows the links to be created on the Index
@@ -55,3 +57,11 @@ func (s SpreadCli) Link() *cli.Command {
 		},
 	}
 }
+
+// resolveLinks replaces the links in docs with the values they point to. Targets in other commits are read from proj,
+// with Secrets decrypted.
+func resolveLinks(proj *project.Project, docs map[string]*pb.Document) error {
+	return data.ResolveLinks(docs, func(sri *data.SRI) (*pb.Document, error) {
+		return proj.GetDecryptedDocument(sri.Treeish, sri.Path)
+	})
+}
@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/codegangsta/cli"
+	"golang.org/x/net/context"
+
+	"rsprd.com/spread/pkg/config"
+	"rsprd.com/spread/pkg/packages"
+	"rsprd.com/spread/pkg/project"
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+const (
+	// defaultPackageBranch is the branch deployed when no version of a package is requested.
+	defaultPackageBranch = "master"
+)
+
+// packageFlags are the flags of commands that retrieve packages.
+var packageFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "offline",
+		Usage: "only use packages that have already been fetched",
+	},
+	cli.BoolFlag{
+		Name:  "allow-unsigned",
+		Usage: "use packages that are not signed by a trusted key",
+	},
+}
+
+// packageOptions control how packages are retrieved.
+type packageOptions struct {
+	// offline prevents network access, only data already stored in the global project is used.
+	offline bool
+	// allowUnsigned skips signature verification of package commits.
+	allowUnsigned bool
+}
+
+// packageOptionsFromContext returns the packageOptions set by packageFlags.
+func packageOptionsFromContext(c *cli.Context) packageOptions {
+	return packageOptions{
+		offline:       c.Bool("offline"),
+		allowUnsigned: c.Bool("allow-unsigned"),
+	}
+}
+
+// packageSource retrieves packages into the global project.
+type packageSource struct {
+	// ctx cancels fetches of packages.
+	ctx      context.Context
+	global   *project.Project
+	resolver *packages.Resolver
+	// verifier is nil if signatures are not checked.
+	verifier *packages.Verifier
+}
+
+// newPackageSource sets up the global project and configures package retrieval using the user's configuration.
+func (s *SpreadCli) newPackageSource(ctx context.Context, opts packageOptions) (*packageSource, error) {
+	global, err := s.globalProject()
+	if err != nil {
+		return nil, fmt.Errorf("error setting up global project: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	cache, err := packages.OpenCache(filepath.Join(global.Path, packages.CacheFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := packages.NewResolver(cfg.Packages, cache)
+	if err != nil {
+		return nil, err
+	}
+	resolver.Offline = resolver.Offline || opts.offline
+
+	src := &packageSource{
+		ctx:      ctx,
+		global:   global,
+		resolver: resolver,
+	}
+
+	if !opts.allowUnsigned {
+		src.verifier = packages.NewVerifier(cfg.Trust)
+	}
+	return src, nil
+}
+
+// packageDocs returns the documents of the package referenced by ref, given as name[@version]. If local is not nil,
+// the commit pinned in its lock file is used; packages that aren't pinned yet are added to the lock file.
+func (s *SpreadCli) packageDocs(ctx context.Context, ref string, local *project.Project, opts packageOptions) (map[string]*pb.Document, error) {
+	name, version := packages.SplitVersion(ref)
+	name, err := packages.ExpandPackageName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := s.newPackageSource(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock *packages.Lock
+	if local != nil {
+		if lock, err = packages.ReadLock(local.LockPath()); err != nil {
+			return nil, err
+		}
+
+		if pkg, ok := lock.Get(name); ok && pkg.Version == version {
+			s.printf("using %s pinned to %s", name, pkg.Commit)
+			return s.lockedPackageDocs(src, pkg)
+		}
+	}
+
+	pkg, err := s.fetchPackage(src, name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := src.global.ResolveCommit(pkg.Commit)
+	if err != nil {
+		return nil, err
+	}
+
+	if lock != nil {
+		lock.Set(pkg)
+		if err = lock.Write(local.LockPath()); err != nil {
+			return nil, err
+		}
+		s.printf("pinned %s to %s", name, pkg.Commit)
+	}
+	return docs, nil
+}
+
+// lockedPackageDocs returns the documents of a pinned package. The package is only fetched if the commit isn't
+// already stored in the global project.
+func (s *SpreadCli) lockedPackageDocs(src *packageSource, pkg packages.LockedPackage) (map[string]*pb.Document, error) {
+	if _, err := src.global.CommitID(pkg.Commit); err != nil {
+		if src.resolver.Offline {
+			return nil, fmt.Errorf("pinned commit for '%s' has not been fetched and offline mode is enabled", pkg.Name)
+		}
+
+		if err = s.packageRemote(src.global, pkg.Name, pkg.RepoURL); err != nil {
+			return nil, err
+		}
+
+		s.printf("pulling repo from %s", pkg.RepoURL)
+		if err = src.global.FetchAll(src.ctx, pkg.Name); err != nil {
+			return nil, fmt.Errorf("failed to fetch '%s': %v", pkg.Name, err)
+		}
+	}
+
+	if err := s.verifyPackage(src, pkg.Name, pkg.Commit); err != nil {
+		return nil, err
+	}
+
+	docs, err := src.global.ResolveCommit(pkg.Commit)
+	if err != nil {
+		return nil, fmt.Errorf("pinned commit for '%s' is not available: %v", pkg.Name, err)
+	}
+	return docs, nil
+}
+
+// fetchPackage discovers the package with name, fetches it into the global project, and resolves version to a commit.
+// When offline, the package is resolved using data fetched previously.
+func (s *SpreadCli) fetchPackage(src *packageSource, name, version string) (packages.LockedPackage, error) {
+	info, err := src.resolver.Discover(name)
+	if err != nil {
+		return packages.LockedPackage{}, fmt.Errorf("failed to retrieve package info: %v", err)
+	}
+
+	if err = s.packageRemote(src.global, name, info.RepoURL); err != nil {
+		return packages.LockedPackage{}, err
+	}
+
+	if src.resolver.Offline {
+		s.printf("offline, using previously fetched data for %s", name)
+	} else {
+		s.printf("pulling repo from %s", info.RepoURL)
+		if err = src.global.FetchAll(src.ctx, name); err != nil {
+			return packages.LockedPackage{}, fmt.Errorf("failed to fetch '%s': %v", name, err)
+		}
+	}
+
+	revision, err := packageRevision(src.global, name, version)
+	if err != nil {
+		return packages.LockedPackage{}, err
+	}
+
+	commit, err := src.global.CommitID(revision)
+	if err != nil {
+		return packages.LockedPackage{}, err
+	}
+
+	if err = s.verifyPackage(src, name, commit); err != nil {
+		return packages.LockedPackage{}, err
+	}
+
+	s.printf("using %s (%s)", revision, commit)
+	return packages.LockedPackage{
+		Name:    name,
+		RepoURL: info.RepoURL,
+		Version: version,
+		Commit:  commit,
+	}, nil
+}
+
+// verifyPackage checks that commit of the package name is signed by a trusted key.
+func (s *SpreadCli) verifyPackage(src *packageSource, name, commit string) error {
+	if src.verifier == nil {
+		return nil
+	}
+
+	raw, err := src.global.RawCommit(commit)
+	if err != nil {
+		return err
+	}
+
+	signer, err := src.verifier.VerifyCommit(raw)
+	if err != nil {
+		return fmt.Errorf("refusing to use '%s' at %s (use --allow-unsigned to override): %v", name, shortOID(commit), err)
+	}
+
+	s.printf("%s at %s is signed by %s", name, shortOID(commit), signer)
+	return nil
+}
+
+// packageRemote ensures the global project has a remote named after the package that points to repoURL.
+func (s *SpreadCli) packageRemote(global *project.Project, name, repoURL string) error {
+	remote, err := global.Remotes().Lookup(name)
+	// if does not exist or has different URL, create new remote
+	if err != nil {
+		if _, err = global.Remotes().Create(name, repoURL); err != nil {
+			return fmt.Errorf("could not create remote: %v", err)
+		}
+	} else if remote.Url() != repoURL {
+		s.printf("changing remote URL for %s, current: '%s' new: '%s'", name, remote.Url(), repoURL)
+		if err = global.Remotes().SetUrl(name, repoURL); err != nil {
+			return fmt.Errorf("failed to change URL for %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// packageRevision resolves the version of a package fetched into the global project to a revision. Semantic version
+// ranges are matched against the package's tags. If no version is given, the default branch is used.
+func packageRevision(proj *project.Project, remoteName, version string) (string, error) {
+	if len(version) == 0 {
+		version = defaultPackageBranch
+	}
+
+	if packages.IsVersionRange(version) {
+		tags, err := proj.RemoteTags(remoteName)
+		if err != nil {
+			return "", err
+		}
+
+		if version, err = packages.MatchVersion(version, tags); err != nil {
+			return "", fmt.Errorf("could not resolve version of '%s': %v", remoteName, err)
+		}
+	}
+
+	return proj.ResolveRemoteRevision(remoteName, version)
+}
@@ -1,9 +1,14 @@
 package cli
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+
 	"github.com/codegangsta/cli"
 
 	"rsprd.com/spread/pkg/data"
+	"rsprd.com/spread/pkg/project"
 	pb "rsprd.com/spread/pkg/spreadproto"
 )
 
@@ -12,7 +17,7 @@ func (s SpreadCli) Param() *cli.Command {
 	return &cli.Command{
 		Name:      "param",
 		Usage:     "Set paramaters for field values in the index",
-		ArgsUsage: "<SRL> <name> <prompt>",
+		ArgsUsage: "<SRL> <name> <prompt> | rm <SRI|name> | edit <SRI|name>",
 		Flags: []cli.Flag{
 			cli.BoolFlag{
 				Name:  "l",
@@ -20,12 +25,36 @@ func (s SpreadCli) Param() *cli.Command {
 			},
 			cli.StringFlag{
 				Name:  "f",
-				Usage: "set Golang format string to use with arguments",
+				Usage: "set pattern to format arguments with, either a template like '{{ lower .Value }}' or a Printf format string",
 			},
 			cli.StringFlag{
 				Name:  "d",
 				Usage: "set default value, interpretted as JSON",
 			},
+			cli.StringFlag{
+				Name:  "type, t",
+				Usage: "restrict arguments to a type: string, number, bool, enum, port, duration, image, or secret",
+			},
+			cli.StringFlag{
+				Name:  "regex",
+				Usage: "regular expression arguments must match",
+			},
+			cli.StringFlag{
+				Name:  "min",
+				Usage: "minimum number, or minimum length of strings",
+			},
+			cli.StringFlag{
+				Name:  "max",
+				Usage: "maximum number, or maximum length of strings",
+			},
+			cli.StringSliceFlag{
+				Name:  "value",
+				Usage: "allowed value of an enum parameter, may be given multiple times",
+			},
+			cli.StringFlag{
+				Name:  "source",
+				Usage: "compute the argument instead of prompting, one of cluster:<path>?<field>, commit:<id|short-id|tag|message>, hash:<SRI>, or secret:<name>",
+			},
 		},
 		Action: func(c *cli.Context) {
 			if c.Bool("l") {
@@ -35,13 +64,23 @@ func (s SpreadCli) Param() *cli.Command {
 					s.fatalf("Could not retrieve index: %v", err)
 				}
 
-				paramFields := data.ParameterFields(docs)
-				for _, field := range paramFields {
-					param := field.GetParam()
+				paramFields, sris := data.ParameterFields(docs), data.ParameterSRIs(docs)
+				for _, name := range data.ParameterNames(paramFields) {
+					fields := paramFields[name]
+					param := fields[0].GetParam()
 					s.printf(" - Name: %s", param.Name)
 					s.printf("   Description: %s", param.Prompt)
 					s.printf("   Pattern: %s", param.Pattern)
-					if param.GetDefault() == nil {
+					if desc := data.DescribeParameter(param); len(desc) != 0 {
+						s.printf("   Type: %s", desc)
+					}
+					s.printf("   Fields:")
+					for _, sri := range sris[name] {
+						s.printf("     %s", sri)
+					}
+					if len(param.Source) != 0 {
+						s.printf("   Source: %s", param.Source)
+					} else if param.GetDefault() == nil {
 						s.printf("   Required: Yes")
 
 					}
@@ -73,27 +112,234 @@ func (s SpreadCli) Param() *cli.Command {
 				Name:    c.Args().Get(1),
 				Prompt:  c.Args().Get(2),
 				Pattern: c.String("f"),
+				Regex:   c.String("regex"),
+				Values:  c.StringSlice("value"),
+				Source:  c.String("source"),
 			}
 
-			// parse default value
-			defaultInput := c.String("d")
-			if len(defaultInput) != 0 {
-				args, err := data.ParseArguments(defaultInput, false)
-				if err != nil {
-					s.fatalf("Could not parse default value: %v", err)
-				} else if len(args) > 1 {
-					s.fatalf("Only one default value can be specified")
+			if typeName := c.String("type"); len(typeName) != 0 {
+				if param.Type, err = data.ParseParameterType(typeName); err != nil {
+					s.fatalf("%v", err)
 				}
-				param.Default = args[0]
+			}
+
+			if param.Min, err = boundArg(c.String("min")); err != nil {
+				s.fatalf("Invalid min: %v", err)
+			}
+
+			if param.Max, err = boundArg(c.String("max")); err != nil {
+				s.fatalf("Invalid max: %v", err)
+			}
+
+			if param.Default, err = defaultArg(c.String("d")); err != nil {
+				s.fatalf("%v", err)
+			}
+
+			if err = data.ValidateParameter(param); err != nil {
+				s.fatalf("Invalid parameter: %v", err)
 			}
 
 			if err = data.AddParamToDoc(doc, target, param); err != nil {
 				s.fatalf("Failed to add parameter: %v", err)
 			}
 
+			// a name used by other fields shares their parameter
+			docs, err := proj.Index()
+			if err != nil {
+				s.fatalf("Could not retrieve index: %v", err)
+			}
+			docs[target.Path] = doc
+
+			if err = data.CheckSharedParameter(data.ParameterFields(docs)[param.Name]); err != nil {
+				s.fatalf("Cannot share parameter: %v", err)
+			}
+
 			if err = proj.AddDocumentToIndex(doc); err != nil {
 				s.fatalf("Failed to add object to Git index: %v", err)
 			}
 		},
+		Subcommands: []cli.Command{
+			{
+				Name:      "rm",
+				Usage:     "remove a parameter from a field, or from every field using it",
+				ArgsUsage: "<SRI|name>",
+				Action: func(c *cli.Context) {
+					proj := s.projectOrDie()
+					docs, targets := s.paramTargets(proj, c.Args().First())
+
+					for _, target := range targets {
+						if err := data.RemoveParamFromDoc(docs[target.Path], target); err != nil {
+							s.fatalf("Failed to remove parameter: %v", err)
+						}
+					}
+
+					if err := proj.AddDocumentsToIndex(targetDocs(docs, targets)...); err != nil {
+						s.fatalf("Failed to add object to Git index: %v", err)
+					}
+
+					for _, target := range targets {
+						s.printf("Removed parameter from %s", target)
+					}
+				},
+			},
+			{
+				Name:      "edit",
+				Usage:     "change the prompt, pattern, default, or source of a parameter",
+				ArgsUsage: "<SRI|name>",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "prompt, p",
+						Usage: "set prompt",
+					},
+					cli.StringFlag{
+						Name:  "f",
+						Usage: "set pattern to format arguments with, either a template like '{{ lower .Value }}' or a Printf format string",
+					},
+					cli.StringFlag{
+						Name:  "d",
+						Usage: "set default value, interpretted as JSON",
+					},
+					cli.BoolFlag{
+						Name:  "no-default",
+						Usage: "remove default value, making the parameter required",
+					},
+					cli.StringFlag{
+						Name:  "source",
+						Usage: "compute the argument instead of prompting, use an empty string to prompt again",
+					},
+				},
+				Action: func(c *cli.Context) {
+					if c.IsSet("d") && c.Bool("no-default") {
+						s.fatalf("A default cannot be both set and removed")
+					}
+
+					def, err := defaultArg(c.String("d"))
+					if err != nil {
+						s.fatalf("%v", err)
+					}
+
+					proj := s.projectOrDie()
+					docs, targets := s.paramTargets(proj, c.Args().First())
+
+					for _, target := range targets {
+						field, err := data.GetFieldFromDocument(docs[target.Path], target.Field)
+						if err != nil {
+							s.fatalf("Could not resolve field: %v", err)
+						}
+
+						param := field.GetParam()
+						if param == nil {
+							s.fatalf("%s does not have a parameter", target)
+						}
+
+						if c.IsSet("prompt") {
+							param.Prompt = c.String("prompt")
+						}
+						if c.IsSet("f") {
+							param.Pattern = c.String("f")
+						}
+						if c.IsSet("source") {
+							param.Source = c.String("source")
+						}
+						if def != nil {
+							param.Default = def
+						} else if c.Bool("no-default") {
+							param.Default = nil
+						}
+
+						if err = data.ValidateParameter(param); err != nil {
+							s.fatalf("Invalid parameter: %v", err)
+						}
+
+						// re-adding clears the value if the parameter became secret
+						if err = data.AddParamToDoc(docs[target.Path], target, param); err != nil {
+							s.fatalf("Failed to update parameter: %v", err)
+						}
+					}
+
+					// editing a single field may leave a shared parameter with different sources
+					for _, fields := range data.ParameterFields(docs) {
+						if err = data.CheckSharedParameter(fields); err != nil {
+							s.fatalf("Cannot share parameter: %v", err)
+						}
+					}
+
+					if err = proj.AddDocumentsToIndex(targetDocs(docs, targets)...); err != nil {
+						s.fatalf("Failed to add object to Git index: %v", err)
+					}
+
+					for _, target := range targets {
+						s.printf("Updated parameter of %s", target)
+					}
+				},
+			},
+		},
+	}
+}
+
+// paramTargets returns the index and the fields addressed by in. In is either the SRI of a field or the name of a
+// parameter, which addresses every field using it.
+func (s SpreadCli) paramTargets(proj *project.Project, in string) (map[string]*pb.Document, []*data.SRI) {
+	if len(in) == 0 {
+		s.fatalf("An SRI or parameter name must be specified")
+	}
+
+	docs, err := proj.Index()
+	if err != nil {
+		s.fatalf("Could not retrieve index: %v", err)
+	}
+
+	if target, err := data.ParseSRI(in); err == nil && target.IsField() {
+		if _, ok := docs[target.Path]; !ok {
+			s.fatalf("Could not find document with path '%s' in index", target.Path)
+		}
+		return docs, []*data.SRI{target}
+	}
+
+	targets := data.ParameterSRIs(docs)[in]
+	if len(targets) == 0 {
+		s.fatalf("No parameter named '%s'", in)
+	}
+	return docs, targets
+}
+
+// targetDocs returns the documents containing targets. Each document is only returned once.
+func targetDocs(docs map[string]*pb.Document, targets []*data.SRI) []*pb.Document {
+	var out []*pb.Document
+	seen := map[string]bool{}
+	for _, target := range targets {
+		if !seen[target.Path] {
+			seen[target.Path] = true
+			out = append(out, docs[target.Path])
+		}
+	}
+	return out
+}
+
+// defaultArg parses the JSON default value of a parameter. Nil is returned if in is empty.
+func defaultArg(in string) (*pb.Argument, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+
+	args, err := data.ParseArguments(in, false)
+	if err != nil {
+		return nil, fmt.Errorf("Could not parse default value: %v", err)
+	} else if len(args) > 1 {
+		return nil, errors.New("Only one default value can be specified")
+	}
+	return args[0], nil
+}
+
+// boundArg parses a min or max constraint. Nil is returned if in is empty.
+func boundArg(in string) (*pb.Argument, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+
+	num, err := strconv.ParseFloat(in, 64)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is not a number", in)
 	}
+	return &pb.Argument{Value: &pb.Argument_Number{Number: num}}, nil
 }
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/pkg/term"
+	"golang.org/x/net/context"
+
+	"rsprd.com/spread/pkg/project"
+)
+
+const (
+	// progressWidth is the number of characters used for the bar itself.
+	progressWidth = 30
+
+	// progressInterval is the minimum time between redraws of the progress bar.
+	progressInterval = 100 * time.Millisecond
+)
+
+// progressBar renders the progress of transfers with remotes on a terminal.
+type progressBar struct {
+	out      io.Writer
+	lastDraw time.Time
+	// drawn is true if the bar is currently displayed on the last line.
+	drawn bool
+}
+
+// Transfer redraws the bar, redraws are limited to one every progressInterval unless the transfer is complete.
+func (b *progressBar) Transfer(stats project.TransferStats) {
+	if stats.TotalObjects == 0 || (!stats.Done() && time.Since(b.lastDraw) < progressInterval) {
+		return
+	}
+	b.lastDraw = time.Now()
+
+	label, current := "Receiving", stats.Objects
+	if stats.Direction == project.Sending {
+		label = "Sending"
+	} else if stats.Objects == stats.TotalObjects {
+		label, current = "Indexing", stats.IndexedObjects
+	}
+
+	filled := int(current * progressWidth / stats.TotalObjects)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressWidth-filled)
+	fmt.Fprintf(b.out, "\r%-9s [%s] %d/%d objects, %s ", label, bar, current, stats.TotalObjects, formatBytes(stats.Bytes))
+	b.drawn = true
+
+	if stats.Done() {
+		b.Finish()
+	}
+}
+
+// Message prints text sent by the remote above the bar.
+func (b *progressBar) Message(text string) {
+	b.Finish()
+	fmt.Fprintf(b.out, "remote: %s", text)
+}
+
+// Finish moves past the bar so other output isn't written over it.
+func (b *progressBar) Finish() {
+	if b.drawn {
+		fmt.Fprintln(b.out)
+		b.drawn = false
+	}
+}
+
+// progress returns a progressBar writing to the error stream, nil is returned if it isn't a terminal.
+func (c SpreadCli) progress() *progressBar {
+	if _, isTerminal := term.GetFdInfo(c.err); !isTerminal {
+		return nil
+	}
+	return &progressBar{out: c.err}
+}
+
+// interruptContext returns a context that is cancelled when the user presses Ctrl-C. Operations on remotes check the
+// context and abort cleanly; a second interrupt exits immediately. Stop must be called once the context is no longer
+// needed.
+func (c SpreadCli) interruptContext() (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+			fmt.Fprintln(c.err, "\nInterrupted, aborting (press Ctrl-C again to force)...")
+			cancel()
+		case <-done:
+			return
+		}
+
+		select {
+		case <-signals:
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		close(done)
+		cancel()
+	}
+}
+
+// formatBytes returns a human readable size.
+func formatBytes(n uint) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := uint(unit), 0
+	for i := n / unit; i >= unit; i /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/codegangsta/cli"
+
+	"rsprd.com/spread/pkg/packages"
+)
+
+// Publish pushes a project to a remote and generates the information needed to discover it as a package.
+func (s SpreadCli) Publish() *cli.Command {
+	return &cli.Command{
+		Name:        "publish",
+		Usage:       "spread publish [-o <dir>] [--serve <addr>] <remote> <package-name>",
+		Description: "Push the project and its tags to a remote and generate discovery pages for the package",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "branch",
+				Value: defaultPackageBranch,
+				Usage: "branch to push",
+			},
+			cli.StringFlag{
+				Name:  "repo-url",
+				Usage: "URL advertised for the repository, defaults to the URL of the remote",
+			},
+			cli.StringFlag{
+				Name:  "o",
+				Usage: "directory to write discovery pages to",
+			},
+			cli.StringFlag{
+				Name:  "serve",
+				Usage: "serve discovery pages on the given address (e.g. ':8080')",
+			},
+			cli.BoolFlag{
+				Name:  "no-push",
+				Usage: "only generate discovery information",
+			},
+		},
+		Action: func(c *cli.Context) {
+			remoteName := c.Args().First()
+			if len(remoteName) == 0 {
+				s.fatalf("a remote must be specified")
+			}
+
+			name := c.Args().Get(1)
+			if len(name) == 0 {
+				s.fatalf("a package name must be specified")
+			}
+
+			name, err := packages.ExpandPackageName(name)
+			if err != nil {
+				s.fatalf("Invalid package name: %v", err)
+			}
+
+			outDir, addr := c.String("o"), c.String("serve")
+			if len(outDir) == 0 && len(addr) == 0 {
+				s.fatalf("an output directory (-o) or address to serve on (--serve) must be specified")
+			}
+
+			p := s.projectOrDie()
+			remote, err := p.Remotes().Lookup(remoteName)
+			if err != nil {
+				s.fatalf("Failed to lookup remote: %v", err)
+			}
+
+			repoURL := c.String("repo-url")
+			if len(repoURL) == 0 {
+				repoURL = remote.Url()
+			}
+
+			if !c.Bool("no-push") {
+				ctx, stop := s.interruptContext()
+
+				branch := fmt.Sprintf("refs/heads/%s", c.String("branch"))
+				if err = p.Push(ctx, remoteName, branch); err != nil {
+					s.fatalf("Failed to push: %v", err)
+				}
+
+				if err = p.PushTags(ctx, remoteName); err != nil {
+					s.fatalf("Failed to push tags: %v", err)
+				}
+				// serving discovery pages should exit on the first interrupt
+				stop()
+				s.printf("Pushed %s and tags to '%s'", branch, remoteName)
+			}
+
+			index := new(packages.Index)
+			if len(outDir) != 0 {
+				if index, err = packages.ReadIndex(outDir); err != nil {
+					s.fatalf("%v", err)
+				}
+			}
+
+			index.Add(packages.PackageInfo{
+				Prefix:  name,
+				RepoURL: repoURL,
+			})
+
+			if len(outDir) != 0 {
+				if err = index.Write(outDir); err != nil {
+					s.fatalf("Could not write discovery pages: %v", err)
+				}
+				s.printf("Wrote discovery pages for %d package(s) to %s", len(index.Packages), outDir)
+			}
+
+			if len(addr) != 0 {
+				s.printf("Serving discovery pages on %s", addr)
+				if err = http.ListenAndServe(addr, index); err != nil {
+					s.fatalf("Failed to serve discovery pages: %v", err)
+				}
+			}
+		},
+	}
+}
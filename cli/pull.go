@@ -29,8 +29,11 @@ func (s SpreadCli) Pull() *cli.Command {
 				refspec = fmt.Sprintf("refs/heads/%s", refspec)
 			}
 
+			ctx, stop := s.interruptContext()
+			defer stop()
+
 			p := s.projectOrDie()
-			if err := p.Pull(remoteName, refspec); err != nil {
+			if err := p.Pull(ctx, remoteName, refspec); err != nil {
 				s.fatalf("Failed to pull: %v", err)
 			}
 		},
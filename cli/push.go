@@ -14,17 +14,24 @@ func (s SpreadCli) Push() *cli.Command {
 		Usage:       "Push references to a remote",
 		ArgsUsage:   "<remote> <refspec>",
 		Description: "Push Spread data to a remote",
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "tags",
+				Usage: "push all tags in addition to any refspecs given",
+			},
+		},
 		Action: func(c *cli.Context) {
 			remoteName := c.Args().First()
 			if len(remoteName) == 0 {
 				s.fatalf("a remote must be specified")
 			}
 
-			if len(c.Args()) < 2 {
+			pushTags := c.Bool("tags")
+			if len(c.Args()) < 2 && !pushTags {
 				s.fatalf("a refspec must be specified")
 			}
 
-			refspecs := c.Args()[1:]
+			refspecs := c.Args().Tail()
 
 			for i, spec := range refspecs {
 				if !strings.HasPrefix(spec, "refs/") {
@@ -32,10 +39,20 @@ func (s SpreadCli) Push() *cli.Command {
 				}
 			}
 
+			ctx, stop := s.interruptContext()
+			defer stop()
+
 			p := s.projectOrDie()
-			err := p.Push(remoteName, refspecs...)
-			if err != nil {
-				s.fatalf("Failed to push: %v", err)
+			if len(refspecs) > 0 {
+				if err := p.Push(ctx, remoteName, refspecs...); err != nil {
+					s.fatalf("Failed to push: %v", err)
+				}
+			}
+
+			if pushTags {
+				if err := p.PushTags(ctx, remoteName); err != nil {
+					s.fatalf("Failed to push tags: %v", err)
+				}
 			}
 		},
 	}
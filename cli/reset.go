@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"github.com/codegangsta/cli"
+)
+
+// Reset sets documents in the index to their state in a commit.
+func (s SpreadCli) Reset() *cli.Command {
+	return &cli.Command{
+		Name:        "reset",
+		Usage:       "spread reset [commit] [path]...",
+		Description: "Reset documents in the index to their state in a commit, unstaging changes. If no commit is given, HEAD is used. If no paths are given, the entire index is reset.",
+		Action: func(c *cli.Context) {
+			proj := s.projectOrDie()
+
+			// the first argument is a commit if it resolves to one, otherwise all arguments are paths
+			revision, paths := "", []string(c.Args())
+			if len(paths) > 0 {
+				if _, err := proj.CommitID(paths[0]); err == nil {
+					revision, paths = paths[0], paths[1:]
+				}
+			}
+
+			if err := proj.ResetIndex(revision, paths...); err != nil {
+				s.fatalf("Could not reset index: %v", err)
+			}
+
+			target := revision
+			if len(target) == 0 {
+				target = "HEAD"
+			}
+
+			if len(paths) == 0 {
+				s.printf("Index reset to %s", target)
+			}
+
+			for _, path := range paths {
+				s.printf("Reset '%s' to %s", path, target)
+			}
+		},
+	}
+}
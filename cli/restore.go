@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"github.com/codegangsta/cli"
+)
+
+// Restore replaces documents in the index with their state in a commit.
+func (s SpreadCli) Restore() *cli.Command {
+	return &cli.Command{
+		Name:        "restore",
+		Usage:       "spread restore [--source <commit>] <path>...",
+		Description: "Restore documents in the index to their state in a commit, for example to recover a document removed with 'spread rm'. Each path must exist in the commit. If no commit is given, HEAD is used.",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "source, s",
+				Value: "HEAD",
+				Usage: "commit to restore documents from",
+			},
+		},
+		Action: func(c *cli.Context) {
+			paths := []string(c.Args())
+			if len(paths) == 0 {
+				s.fatalf("A path to be restored must be specified")
+			}
+
+			proj := s.projectOrDie()
+			source := c.String("source")
+			if err := proj.RestoreIndex(source, paths...); err != nil {
+				s.fatalf("Could not restore: %v", err)
+			}
+
+			for _, path := range paths {
+				s.printf("Restored '%s' from %s", path, source)
+			}
+		},
+	}
+}
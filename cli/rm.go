@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"github.com/codegangsta/cli"
+)
+
+// Remove removes documents from the index.
+func (s SpreadCli) Remove() *cli.Command {
+	return &cli.Command{
+		Name:        "rm",
+		Usage:       "spread rm <path>...",
+		Description: "Remove documents from the index. If a path is a directory, all documents within it are removed. The removal is recorded by the next commit.",
+		Action: func(c *cli.Context) {
+			paths := c.Args()
+			if len(paths) == 0 {
+				s.fatalf("A path to be removed must be specified")
+			}
+
+			proj := s.projectOrDie()
+			for _, path := range paths {
+				removed, err := proj.RemoveFromIndex(path)
+				if err != nil {
+					s.fatalf("Could not remove '%s': %v", path, err)
+				}
+
+				for _, doc := range removed {
+					s.printf("rm '%s'", doc)
+				}
+			}
+		},
+	}
+}
@@ -0,0 +1,319 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/codegangsta/cli"
+
+	"rsprd.com/spread/pkg/config"
+	"rsprd.com/spread/pkg/data"
+	"rsprd.com/spread/pkg/project"
+	"rsprd.com/spread/pkg/secrets"
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+// Secrets manages the project's local store of encrypted secrets.
+func (s SpreadCli) Secrets() *cli.Command {
+	return &cli.Command{
+		Name:        "secrets",
+		Usage:       "View/modify secrets and who Secret documents are encrypted for",
+		Description: "Manages secrets stored encrypted in the project, outside of versioned documents. Parameters use them with --source secret:<name>.",
+		Action: func(c *cli.Context) {
+			store := s.secretStoreOrDie(s.projectOrDie(), false)
+
+			s.printf("Secrets:")
+			for _, name := range store.Names() {
+				s.printf("- %s", name)
+			}
+
+			println()
+			cli.ShowSubcommandHelp(c)
+		},
+		HideHelp: true,
+		Subcommands: []cli.Command{
+			{
+				Name:      "set",
+				Usage:     "store a secret, read from a prompt or stdin",
+				ArgsUsage: "<name>",
+				Action: func(c *cli.Context) {
+					name := c.Args().First()
+					if len(name) == 0 {
+						s.fatalf("a name must be specified")
+					}
+
+					store := s.secretStoreOrDie(s.projectOrDie(), true)
+
+					// prompt if possible, otherwise read the secret from stdin
+					value, err := s.promptHidden("Value: ")
+					if err != nil {
+						in, readErr := ioutil.ReadAll(s.in)
+						if readErr != nil {
+							s.fatalf("Could not read secret: %v", readErr)
+						}
+						value = strings.TrimRight(string(in), "\r\n")
+					}
+
+					if err = store.Set(name, value); err != nil {
+						s.fatalf("Could not store secret: %v", err)
+					} else if err = store.Save(); err != nil {
+						s.fatalf("%v", err)
+					}
+
+					s.printf("Stored secret '%s'", name)
+				},
+			},
+			{
+				Name:      "rm",
+				Usage:     "delete a secret",
+				ArgsUsage: "<name>",
+				Action: func(c *cli.Context) {
+					name := c.Args().First()
+					if len(name) == 0 {
+						s.fatalf("a name must be specified")
+					}
+
+					store := s.secretStoreOrDie(s.projectOrDie(), false)
+					if !store.Remove(name) {
+						s.fatalf("No secret named '%s'", name)
+					} else if err := store.Save(); err != nil {
+						s.fatalf("%v", err)
+					}
+
+					s.printf("Removed secret '%s'", name)
+				},
+			},
+			{
+				Name:  "identity",
+				Usage: "display your public key, creating it if needed",
+				Action: func(c *cli.Context) {
+					id := s.identityOrDie(true)
+					s.printf("%s", id.Public)
+				},
+			},
+			{
+				Name:        "recipients",
+				Usage:       "View/modify who Secret documents are encrypted for",
+				Description: "Secret documents are encrypted for every recipient when written. Changing recipients re-encrypts the Secrets in the index.",
+				Action: func(c *cli.Context) {
+					recipients, err := s.projectOrDie().Recipients()
+					if err != nil {
+						s.fatalf("%v", err)
+					}
+
+					s.printf("Recipients:")
+					for _, recipient := range recipients {
+						s.printf("- %s %s", recipient.Name, recipient.Key)
+					}
+
+					println()
+					cli.ShowSubcommandHelp(c)
+				},
+				HideHelp: true,
+				Subcommands: []cli.Command{
+					{
+						Name:      "add",
+						Usage:     "encrypt Secrets for a public key, your own if none is given",
+						ArgsUsage: "<name> [public key]",
+						Action: func(c *cli.Context) {
+							name := c.Args().First()
+							if len(name) == 0 {
+								s.fatalf("a name must be specified")
+							}
+
+							var key secrets.PublicKey
+							var err error
+							if in := c.Args().Get(1); len(in) != 0 {
+								if key, err = secrets.ParsePublicKey(in); err != nil {
+									s.fatalf("%v", err)
+								}
+							} else {
+								key = s.identityOrDie(true).Public
+							}
+
+							proj := s.projectOrDie()
+							recipients, err := proj.Recipients()
+							if err != nil {
+								s.fatalf("%v", err)
+							}
+
+							if recipients, err = recipients.Add(secrets.Recipient{Name: name, Key: key}); err != nil {
+								s.fatalf("Could not add recipient: %v", err)
+							}
+
+							count := s.setRecipients(proj, recipients)
+							s.printf("Added recipient '%s', re-encrypted %d Secrets in the index", name, count)
+						},
+					},
+					{
+						Name:      "rm",
+						Usage:     "stop encrypting Secrets for a recipient",
+						ArgsUsage: "<name>",
+						Action: func(c *cli.Context) {
+							name := c.Args().First()
+							if len(name) == 0 {
+								s.fatalf("a name must be specified")
+							}
+
+							proj := s.projectOrDie()
+							recipients, err := proj.Recipients()
+							if err != nil {
+								s.fatalf("%v", err)
+							}
+
+							recipients, removed := recipients.Remove(name)
+							if !removed {
+								s.fatalf("No recipient named '%s'", name)
+							}
+
+							count := s.setRecipients(proj, recipients)
+							s.printf("Removed recipient '%s', re-encrypted %d Secrets in the index", name, count)
+							s.printf("Previous commits can still be decrypted by '%s'", name)
+							if len(recipients) == 0 && proj.PlaintextSecrets {
+								s.printf("There are no recipients left, Secrets will be stored unencrypted")
+							} else if len(recipients) == 0 {
+								s.printf("There are no recipients left, Secrets can't be added until one is")
+							}
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// setRecipients saves recipients for proj and re-encrypts the Secret documents in the index for them. The number of
+// documents re-encrypted is returned.
+func (s SpreadCli) setRecipients(proj *project.Project, recipients secrets.Recipients) int {
+	docs, err := proj.Index()
+	if err != nil {
+		s.fatalf("Could not retrieve index: %v", err)
+	}
+
+	secretDocs := map[string]*pb.Document{}
+	for path, doc := range docs {
+		if secrets.IsSecretDocument(doc) {
+			secretDocs[path] = doc
+		}
+	}
+
+	if len(recipients) == 0 && len(secretDocs) != 0 && !proj.PlaintextSecrets {
+		s.fatalf("Removing the last recipient would leave %d Secrets in the index unencrypted, set plaintextSecrets in your configuration to allow it",
+			len(secretDocs))
+	}
+
+	// decrypt before changing recipients so a failure leaves everything as it was
+	if err = s.decryptSecrets(secretDocs); err != nil {
+		s.fatalf("%v", err)
+	}
+
+	if err = recipients.Write(proj.RecipientsPath()); err != nil {
+		s.fatalf("%v", err)
+	}
+
+	toAdd := make([]*pb.Document, 0, len(secretDocs))
+	for _, doc := range secretDocs {
+		toAdd = append(toAdd, doc)
+	}
+
+	if err = proj.AddDocumentsToIndex(toAdd...); err != nil {
+		s.fatalf("Failed to add object to Git index: %v", err)
+	}
+	return len(secretDocs)
+}
+
+// decryptSecrets decrypts the Secret documents in docs using the user's identity.
+func (s SpreadCli) decryptSecrets(docs map[string]*pb.Document) error {
+	var id *secrets.Identity
+	for path, doc := range docs {
+		if !secrets.IsEncrypted(doc) {
+			continue
+		}
+
+		if id == nil {
+			var err error
+			if id, err = s.identity(false); err != nil {
+				return fmt.Errorf("could not decrypt '%s': %v", path, err)
+			}
+		}
+
+		if err := secrets.DecryptDocument(doc, id); err != nil {
+			return fmt.Errorf("could not decrypt '%s': %v", path, err)
+		}
+	}
+	return nil
+}
+
+// identity loads the user's identity from the location in their configuration. If create is true, the identity is
+// generated if it doesn't exist.
+func (s SpreadCli) identity(create bool) (*secrets.Identity, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	path := cfg.SecretIdentity
+	if len(path) == 0 {
+		path = secrets.DefaultIdentityFile
+	}
+	return secrets.LoadIdentity(path, create)
+}
+
+func (s SpreadCli) identityOrDie(create bool) *secrets.Identity {
+	id, err := s.identity(create)
+	if err != nil {
+		s.fatalf("Could not load identity: %v", err)
+	}
+	return id
+}
+
+// secretStore opens the secret store of proj using the key from the user's configuration. If create is true, the key
+// is generated if it doesn't exist.
+func (s SpreadCli) secretStore(proj *project.Project, create bool) (*secrets.Store, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	keyPath := cfg.SecretKey
+	if len(keyPath) == 0 {
+		keyPath = secrets.DefaultKeyFile
+	}
+
+	key, err := secrets.LoadKey(keyPath, create)
+	if err != nil {
+		return nil, err
+	}
+	return secrets.OpenStore(proj.SecretStorePath(), key)
+}
+
+func (s SpreadCli) secretStoreOrDie(proj *project.Project, create bool) *secrets.Store {
+	store, err := s.secretStore(proj, create)
+	if err != nil {
+		s.fatalf("Could not open secret store: %v", err)
+	}
+	return store
+}
+
+// secretSource returns a SourceFunc which reads secrets from the store of proj. The store is only opened when first
+// used.
+func (s SpreadCli) secretSource(proj *project.Project) data.SourceFunc {
+	var store *secrets.Store
+	return func(target string, _ map[string]*pb.Document) (*pb.Argument, error) {
+		var err error
+		if store == nil {
+			if store, err = s.secretStore(proj, false); err != nil {
+				return nil, err
+			}
+		}
+
+		val, ok, err := store.Get(target)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, fmt.Errorf("no secret named '%s', add it with `spread secrets set %s`", target, target)
+		}
+		return &pb.Argument{Value: &pb.Argument_Str{Str: val}}, nil
+	}
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"github.com/codegangsta/cli"
+	"github.com/golang/protobuf/proto"
+
+	"rsprd.com/spread/pkg/data"
+	"rsprd.com/spread/pkg/deploy"
+	"rsprd.com/spread/pkg/project"
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+// Set changes the value of a field of a document in the index.
+func (s SpreadCli) Set() *cli.Command {
+	return &cli.Command{
+		Name:        "set",
+		Usage:       "spread set <SRI> <json-value>",
+		Description: "Set a field of a staged document to a JSON value. Objects and arrays along the path are created as needed; an array index equal to the length of the array appends to it.",
+		Action: func(c *cli.Context) {
+			if len(c.Args()) != 2 {
+				s.fatalf("An SRI and a JSON value must be specified")
+			}
+
+			value, err := data.FieldFromJSON("", c.Args().Get(1))
+			if err != nil {
+				s.fatalf("Invalid value: %v", err)
+			}
+
+			proj := s.projectOrDie()
+			target, doc := s.fieldTarget(proj, c.Args().First())
+			s.checkReplaceable(target, doc)
+
+			if err = data.SetField(doc.GetRoot(), target.Field, value); err != nil {
+				s.fatalf("Could not set %s: %v", target, err)
+			}
+
+			s.restageDocument(proj, target.Path, doc)
+			s.printf("Set %s", target)
+		},
+	}
+}
+
+// Unset removes a field from a document in the index.
+func (s SpreadCli) Unset() *cli.Command {
+	return &cli.Command{
+		Name:        "unset",
+		Usage:       "spread unset <SRI>",
+		Description: "Remove a field from a staged document. Removing an item from an array shifts the items after it.",
+		Action: func(c *cli.Context) {
+			if len(c.Args()) != 1 {
+				s.fatalf("An SRI must be specified")
+			}
+
+			proj := s.projectOrDie()
+			target, doc := s.fieldTarget(proj, c.Args().First())
+			s.checkReplaceable(target, doc)
+
+			if err := data.UnsetField(doc.GetRoot(), target.Field); err != nil {
+				s.fatalf("Could not unset %s: %v", target, err)
+			}
+
+			s.restageDocument(proj, target.Path, doc)
+			s.printf("Unset %s", target)
+		},
+	}
+}
+
+// fieldTarget parses the SRI of a field and returns it with the document it addresses from the index.
+func (s SpreadCli) fieldTarget(proj *project.Project, in string) (*data.SRI, *pb.Document) {
+	if len(in) == 0 {
+		s.fatalf("A target SRI must be specified")
+	}
+
+	target, err := data.ParseSRI(in)
+	if err != nil {
+		s.fatalf("Error using target: %v", err)
+	} else if !target.IsField() {
+		s.fatalf("%s does not address a field", target)
+	}
+
+	doc, err := proj.DocFromIndex(target.Path)
+	if err != nil {
+		s.fatalf("Error retrieving from index: %v", err)
+	} else if doc.GetRoot() == nil {
+		s.fatalf("Document '%s' does not have a root", target.Path)
+	}
+	return target, doc
+}
+
+// checkReplaceable exits if setting or unsetting the field addressed by target would discard a parameter or link within
+// it. The field may itself be a link, since this is how links are replaced or removed.
+func (s SpreadCli) checkReplaceable(target *data.SRI, doc *pb.Document) {
+	field, err := data.GetFieldFromDocument(doc, target.Field)
+	if err != nil {
+		// the field will be created
+		return
+	}
+
+	path, found := data.FindParamOrLink(field, target.Field)
+	if found == nil || (found == field && found.GetParam() == nil) {
+		return
+	}
+
+	sri := &data.SRI{Treeish: target.Treeish, Path: target.Path, Field: path}
+	if param := found.GetParam(); param != nil {
+		s.fatalf("%s has parameter '%s', remove it with 'spread param rm' first", sri, param.Name)
+	}
+	s.fatalf("%s is a link, unset it first", sri)
+}
+
+// restageDocument checks that doc is still a valid Kubernetes object for path and adds it to the index.
+func (s SpreadCli) restageDocument(proj *project.Project, path string, doc *pb.Document) {
+	// check a decrypted copy so encrypted Secret data can be decoded
+	check := proto.Clone(doc).(*pb.Document)
+	if err := s.decryptSecrets(map[string]*pb.Document{path: check}); err != nil {
+		s.fatalf("Could not check document: %v", err)
+	}
+
+	if _, err := deploy.KubeObjectFromDocument(path, check); err != nil {
+		s.fatalf("Document '%s' would no longer be a valid object: %v", path, err)
+	}
+
+	if err := proj.AddDocumentToIndex(doc); err != nil {
+		s.fatalf("Failed to add object to Git index: %v", err)
+	}
+}
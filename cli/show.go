@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/codegangsta/cli"
+	"github.com/golang/protobuf/proto"
 
 	"rsprd.com/spread/pkg/data"
 	pb "rsprd.com/spread/pkg/spreadproto"
@@ -29,11 +30,14 @@ func (s SpreadCli) Show() *cli.Command {
 				if doc, ok = docs[path]; !ok {
 					s.fatalf("Path '%s' not found in index.", path)
 				}
+				if err = s.decryptSecrets(map[string]*pb.Document{path: doc}); err != nil {
+					s.fatalf("%v", err)
+				}
 			case 2: // from revision
 				revision := c.Args().First()
 				path := c.Args().Get(1)
 				var err error
-				doc, err = p.GetDocument(revision, path)
+				doc, err = p.GetDecryptedDocument(revision, path)
 				if err != nil {
 					s.fatalf("failed to get document: %v", err)
 				}
@@ -41,6 +45,10 @@ func (s SpreadCli) Show() *cli.Command {
 				s.fatalf("a path OR path and revision must be specified")
 			}
 
+			// values of secret parameters are never displayed
+			doc = proto.Clone(doc).(*pb.Document)
+			data.MaskSecrets(doc.GetRoot())
+
 			fields, err := data.MapFromDocument(doc)
 			if err != nil {
 				s.fatalf("could not get fields: %v", err)
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"rsprd.com/spread/pkg/data"
+	"rsprd.com/spread/pkg/deploy"
+	"rsprd.com/spread/pkg/project"
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+// paramSources returns the sources used to compute arguments for documents from revision of proj. Cluster sources
+// connect using the given kubectl context. If proj is nil, commit and secret sources are unavailable and hash sources
+// must be relative.
+func (s *SpreadCli) paramSources(proj *project.Project, revision, context string) data.Sources {
+	sources := data.Sources{
+		data.SourceCluster: clusterSource(context),
+		data.SourceHash:    data.HashSource(nil),
+	}
+
+	if proj != nil {
+		sources[data.SourceCommit] = commitSource(proj, revision)
+		sources[data.SourceSecret] = s.secretSource(proj)
+		sources[data.SourceHash] = data.HashSource(func(sri *data.SRI) (*pb.Document, error) {
+			return proj.GetDecryptedDocument(sri.Treeish, sri.Path)
+		})
+	}
+	return sources
+}
+
+// clusterSource returns a SourceFunc which reads fields of objects deployed to the cluster of context. The cluster is
+// only connected to when first used.
+func clusterSource(context string) data.SourceFunc {
+	var cluster *deploy.KubeCluster
+	return func(target string, _ map[string]*pb.Document) (*pb.Argument, error) {
+		sri, err := data.ParseSRI("*/" + target)
+		if err != nil {
+			return nil, err
+		}
+
+		if cluster == nil {
+			if cluster, err = deploy.NewKubeClusterFromContext(context); err != nil {
+				return nil, err
+			}
+		}
+
+		obj, err := cluster.GetPath(sri.Path, true)
+		if err != nil {
+			return nil, err
+		}
+
+		doc, err := data.CreateDocument(obj.GetObjectMeta().GetName(), sri.Path, obj)
+		if err != nil {
+			return nil, err
+		}
+
+		field, err := data.GetFieldFromDocument(doc, sri.Field)
+		if err != nil {
+			return nil, err
+		}
+		return data.ArgumentFromField(field)
+	}
+}
+
+// commitSource returns a SourceFunc which describes the commit specified by revision.
+func commitSource(proj *project.Project, revision string) data.SourceFunc {
+	return func(target string, _ map[string]*pb.Document) (*pb.Argument, error) {
+		var val string
+		var err error
+		switch target {
+		case "id":
+			val, err = proj.CommitID(revision)
+		case "short-id":
+			if val, err = proj.CommitID(revision); err == nil {
+				val = val[:data.MinObjectIDLen]
+			}
+		case "tag":
+			val, err = proj.LatestTag(revision)
+		case "message":
+			val, err = proj.CommitMessage(revision)
+			val = strings.TrimSpace(val)
+		default:
+			err = fmt.Errorf("unknown commit source '%s'", target)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+		return &pb.Argument{Value: &pb.Argument_Str{Str: val}}, nil
+	}
+}
@@ -19,6 +19,11 @@ func (s SpreadCli) Status() *cli.Command {
 				s.fatalf("Could not load Index: %v", err)
 			}
 
+			// encrypting the same Secret twice gives different ciphertext, so compare plaintext
+			if err = s.decryptSecrets(indexDocs); err != nil {
+				s.fatalf("%v", err)
+			}
+
 			index, err := deploy.DeploymentFromDocMap(indexDocs)
 			if err != nil {
 				s.fatalf("Failed to create KubeObject from index doc: %v", err)
@@ -27,6 +32,10 @@ func (s SpreadCli) Status() *cli.Command {
 			var head *deploy.Deployment
 			headDocs, err := proj.Head()
 			if err == nil {
+				if err = s.decryptSecrets(headDocs); err != nil {
+					s.fatalf("%v", err)
+				}
+
 				head, err = deploy.DeploymentFromDocMap(headDocs)
 				if err != nil {
 					s.fatalf("Failed to create KubeObject from HEAD doc: %v", err)
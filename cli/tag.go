@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/codegangsta/cli"
+
+	"rsprd.com/spread/pkg/project"
+)
+
+// Tag creates a release tag for a commit.
+func (s SpreadCli) Tag() *cli.Command {
+	return &cli.Command{
+		Name:        "tag",
+		Usage:       "spread tag [-m <msg>] <name> [commit]",
+		Description: "Create an annotated tag for a commit. If no commit is given, HEAD is used.",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "m",
+				Usage: "Message to store the tag with",
+			},
+		},
+		Action: func(c *cli.Context) {
+			name := c.Args().First()
+			if len(name) == 0 {
+				s.fatalf("A tag name must be specified")
+			}
+
+			revision := c.Args().Get(1)
+
+			msg := c.String("m")
+			if len(msg) == 0 {
+				msg = name
+			}
+
+			proj := s.projectOrDie()
+			notImplemented := project.Person{
+				Name:  "not implemented",
+				Email: "not@implemented.com",
+				When:  time.Now(),
+			}
+
+			if _, err := proj.Tag(name, revision, notImplemented, msg); err != nil {
+				s.fatalf("Could not create tag: %v", err)
+			}
+
+			s.printf("Created tag '%s'", name)
+		},
+	}
+}
+
+// Tags lists the tags in a project.
+func (s SpreadCli) Tags() *cli.Command {
+	return &cli.Command{
+		Name:        "tags",
+		Usage:       "spread tags",
+		Description: "List tags stored in the repository",
+		Action: func(c *cli.Context) {
+			proj := s.projectOrDie()
+			tags, err := proj.Tags()
+			if err != nil {
+				s.fatalf("Could not retrieve tags: %v", err)
+			}
+
+			for _, tag := range tags {
+				s.printf("%s\t%s\t%s", tag.Name, shortOID(tag.Commit), firstLine(tag.Message))
+			}
+		},
+	}
+}
+
+// shortOID abbreviates a hex object ID for display.
+func shortOID(oid string) string {
+	if len(oid) > 7 {
+		return oid[:7]
+	}
+	return oid
+}
+
+// firstLine returns the first line of msg.
+func firstLine(msg string) string {
+	for i, r := range msg {
+		if r == '\n' {
+			return msg[:i]
+		}
+	}
+	return msg
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"github.com/codegangsta/cli"
+
+	"rsprd.com/spread/pkg/packages"
+)
+
+// Update re-resolves the packages pinned in the project's lock file.
+func (s *SpreadCli) Update() *cli.Command {
+	return &cli.Command{
+		Name:        "update",
+		Usage:       "spread update [package[@version]]",
+		Description: "Fetch packages and pin them to the latest commit matching their version. If no package is given, all pinned packages are updated.",
+		Flags:       packageFlags,
+		Action: func(c *cli.Context) {
+			proj := s.projectOrDie()
+			lock, err := packages.ReadLock(proj.LockPath())
+			if err != nil {
+				s.fatalf("Could not read lock file: %v", err)
+			}
+
+			ctx, stop := s.interruptContext()
+			defer stop()
+
+			src, err := s.newPackageSource(ctx, packageOptionsFromContext(c))
+			if err != nil {
+				s.fatalf("Could not setup package retrieval: %v", err)
+			}
+
+			pinned := lock.Packages()
+			if ref := c.Args().First(); len(ref) != 0 {
+				name, version := packages.SplitVersion(ref)
+				if name, err = packages.ExpandPackageName(name); err != nil {
+					s.fatalf("Invalid package name: %v", err)
+				}
+
+				pkg, ok := lock.Get(name)
+				if !ok {
+					s.fatalf("'%s' is not pinned in %s", name, packages.LockFileName)
+				}
+
+				// a new version may be requested
+				if len(version) != 0 {
+					pkg.Version = version
+				}
+				pinned = []packages.LockedPackage{pkg}
+			}
+
+			for _, old := range pinned {
+				pkg, err := s.fetchPackage(src, old.Name, old.Version)
+				if err != nil {
+					s.fatalf("Could not update '%s': %v", old.Name, err)
+				}
+
+				lock.Set(pkg)
+				if pkg.Commit != old.Commit {
+					s.printf("Updated %s: %s -> %s", pkg.Name, shortOID(old.Commit), shortOID(pkg.Commit))
+				}
+			}
+
+			if err = lock.Write(proj.LockPath()); err != nil {
+				s.fatalf("%v", err)
+			}
+		},
+	}
+}
@@ -1,15 +1,159 @@
 package config
 
 import (
+	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/mitchellh/go-homedir"
 )
 
 // Out is the Writer that debugging information is written to.
 var Out io.Writer
 
+// Path is the location of the user's Spread configuration file. The '~' character may be used to denote the home
+// directory of a user across platforms. It can be overridden with the environment variable named by PathEnv.
+var Path = "~/.spread.yaml"
+
+const (
+	// PathEnv is the name of the environment variable that overrides Path.
+	PathEnv = "SPREAD_CONFIG"
+
+	// DefaultDiscoveryTTL is how long package discovery results are cached if no TTL is configured.
+	DefaultDiscoveryTTL = 24 * time.Hour
+)
+
 func init() {
 	if Out == nil {
 		Out = os.Stdout
 	}
 }
+
+// Config holds user configuration for Spread.
+type Config struct {
+	// Packages configures how packages are discovered and retrieved.
+	Packages PackageConfig `json:"packages"`
+
+	// Trust is the set of keys trusted to sign package commits.
+	Trust TrustConfig `json:"trust"`
+
+	// Credentials configures how credentials for remotes are found.
+	Credentials CredentialConfig `json:"credentials"`
+
+	// KnownHosts are files with trusted SSH host keys in OpenSSH known_hosts format. If empty, the user's and the
+	// system's known_hosts files are used.
+	KnownHosts []string `json:"knownHosts"`
+
+	// Remotes configures individual remotes. Keys are remote names or URLs.
+	Remotes map[string]RemoteConfig `json:"remotes"`
+
+	// SecretKey is the path of the key encrypting the secret stores of projects. If empty, "~/.spread-secret.key" is
+	// used.
+	SecretKey string `json:"secretKey"`
+
+	// SecretIdentity is the path of the private key used to decrypt Secret documents. If empty, "~/.spread-identity"
+	// is used.
+	SecretIdentity string `json:"secretIdentity"`
+
+	// PlaintextSecrets allows Secret documents to be committed unencrypted in projects without recipients.
+	PlaintextSecrets bool `json:"plaintextSecrets"`
+}
+
+// PackageConfig configures how packages are discovered and retrieved.
+type PackageConfig struct {
+	// Offline disables network access for discovery and fetching. Only cached data is used.
+	Offline bool `json:"offline"`
+
+	// DiscoveryTTL is how long discovery results are cached, formatted as a Go duration (e.g. "12h").
+	DiscoveryTTL string `json:"discoveryTTL"`
+
+	// Mirrors maps package name prefixes to alternative repository URLs or local paths.
+	Mirrors map[string]string `json:"mirrors"`
+
+	// InsecureDiscovery allows discovery to fallback to HTTP if a package can't be discovered over HTTPS.
+	InsecureDiscovery bool `json:"insecureDiscovery"`
+}
+
+// TrustConfig is the set of keys trusted to sign package commits. Packages must be signed by one of them unless
+// signature verification is explicitly skipped.
+type TrustConfig struct {
+	// GPGKeys are the fingerprints or long key IDs of trusted GPG keys. The keys must be in the user's keyring.
+	GPGKeys []string `json:"gpgKeys"`
+
+	// SSHKeys are trusted SSH public keys in authorized_keys format (e.g. "ssh-ed25519 AAAA... user@host").
+	SSHKeys []string `json:"sshKeys"`
+
+	// GPGProgram is the gpg executable to use, "gpg" is used if not set.
+	GPGProgram string `json:"gpgProgram"`
+}
+
+// CredentialConfig configures how credentials for remotes are found.
+type CredentialConfig struct {
+	// DisableAgent prevents keys held by ssh-agent from being used.
+	DisableAgent bool `json:"disableAgent"`
+
+	// SSHKeys are paths to private keys used for SSH remotes. The public key must be stored next to the private key
+	// with the suffix ".pub".
+	SSHKeys []string `json:"sshKeys"`
+
+	// File is the path of a file with credentials for HTTPS remotes in the format used by git-credential-store.
+	File string `json:"file"`
+
+	// Helpers are Git credential helpers queried for HTTPS remotes, specified like Git's credential.helper option.
+	Helpers []string `json:"helpers"`
+}
+
+// RemoteConfig configures connections to a single remote.
+type RemoteConfig struct {
+	// Insecure disables verification of the remote's SSH host key or TLS certificate.
+	Insecure bool `json:"insecure"`
+}
+
+// TTL returns the parsed DiscoveryTTL. DefaultDiscoveryTTL is returned if none is set.
+func (c PackageConfig) TTL() (time.Duration, error) {
+	if len(c.DiscoveryTTL) == 0 {
+		return DefaultDiscoveryTTL, nil
+	}
+
+	ttl, err := time.ParseDuration(c.DiscoveryTTL)
+	if err != nil {
+		return 0, fmt.Errorf("invalid discovery TTL: %v", err)
+	}
+	return ttl, nil
+}
+
+// Location returns the expanded path of the configuration file.
+func Location() (string, error) {
+	if env := os.Getenv(PathEnv); len(env) != 0 {
+		return homedir.Expand(env)
+	}
+	return homedir.Expand(Path)
+}
+
+// Load reads the user's configuration file. An empty Config is returned if the file doesn't exist.
+func Load() (*Config, error) {
+	path, err := Location()
+	if err != nil {
+		return nil, err
+	}
+	return Read(path)
+}
+
+// Read parses the YAML or JSON configuration file at path. An empty Config is returned if the file doesn't exist.
+func Read(path string) (*Config, error) {
+	cfg := new(Config)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read config: %v", err)
+	}
+
+	if err = yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("could not parse config '%s': %v", path, err)
+	}
+	return cfg, nil
+}
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestSetupOut(t *testing.T) {
@@ -10,3 +13,62 @@ func TestSetupOut(t *testing.T) {
 		t.Error("Out should have been set to use STDOUT")
 	}
 }
+
+func TestReadMissing(t *testing.T) {
+	cfg, err := Read(filepath.Join(os.TempDir(), "spread-config-does-not-exist.yaml"))
+	if err != nil {
+		t.Fatalf("missing config should not error: %v", err)
+	}
+
+	if ttl, err := cfg.Packages.TTL(); err != nil || ttl != DefaultDiscoveryTTL {
+		t.Errorf("expected default TTL, got %v (err: %v)", ttl, err)
+	}
+}
+
+func TestRead(t *testing.T) {
+	f, err := ioutil.TempFile("", "spread-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	data := `packages:
+  offline: true
+  discoveryTTL: 1h30m
+  insecureDiscovery: true
+  mirrors:
+    redspread.com/library: /srv/spread/library
+remotes:
+  origin:
+    insecure: true
+`
+	if _, err = f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	cfg, err := Read(f.Name())
+	if err != nil {
+		t.Fatalf("could not read config: %v", err)
+	}
+
+	if !cfg.Packages.Offline {
+		t.Error("offline should be set")
+	}
+
+	if ttl, err := cfg.Packages.TTL(); err != nil || ttl != 90*time.Minute {
+		t.Errorf("expected TTL of 1h30m, got %v (err: %v)", ttl, err)
+	}
+
+	if !cfg.Packages.InsecureDiscovery {
+		t.Error("insecure discovery should be set")
+	}
+
+	if mirror := cfg.Packages.Mirrors["redspread.com/library"]; mirror != "/srv/spread/library" {
+		t.Errorf("unexpected mirror '%s'", mirror)
+	}
+
+	if !cfg.Remotes["origin"].Insecure {
+		t.Error("origin should be insecure")
+	}
+}
@@ -0,0 +1,189 @@
+// Package credentials finds the credentials used to authenticate with Git remotes.
+package credentials
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+
+	"rsprd.com/spread/pkg/config"
+)
+
+// Kind is a type of credential.
+type Kind uint
+
+const (
+	// UserPass is a username and password (or token), used for HTTPS remotes.
+	UserPass Kind = 1 << iota
+	// SSHKey is an SSH key pair or a key held by ssh-agent.
+	SSHKey
+)
+
+// DefaultSSHKeys are the private keys tried when none are configured.
+var DefaultSSHKeys = []string{"~/.ssh/id_ed25519", "~/.ssh/id_ecdsa", "~/.ssh/id_rsa"}
+
+// DefaultFile is the credentials file used when none is configured. It uses the format of git-credential-store.
+var DefaultFile = "~/.git-credentials"
+
+// Request describes the remote that credentials are needed for.
+type Request struct {
+	// URL is the location of the remote.
+	URL string
+	// Username is the username given in the URL, if any.
+	Username string
+}
+
+// Credential can be used to authenticate with a remote.
+type Credential struct {
+	Kind     Kind
+	Username string
+	Password string
+
+	// Agent is true if the SSH key should be retrieved from ssh-agent.
+	Agent bool
+	// PublicKey and PrivateKey are paths to an SSH key pair.
+	PublicKey  string
+	PrivateKey string
+	// Passphrase returns the passphrase of PrivateKey. It is nil if the key is not encrypted.
+	Passphrase func() (string, error)
+
+	// Source describes where the credential was found.
+	Source string
+}
+
+// Provider supplies credentials for remotes.
+type Provider interface {
+	// Credentials returns the credentials that should be tried for req in order.
+	Credentials(req Request) ([]Credential, error)
+}
+
+// Chain is a list of Providers which are consulted in order.
+type Chain []Provider
+
+// Credentials returns the credentials of every provider in the Chain. Providers that fail are skipped; an error is
+// only returned if no credentials were found.
+func (c Chain) Credentials(req Request) ([]Credential, error) {
+	var creds []Credential
+	var lastErr error
+	for _, p := range c {
+		found, err := p.Credentials(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		creds = append(creds, found...)
+	}
+
+	if len(creds) == 0 {
+		return nil, lastErr
+	}
+	return creds, nil
+}
+
+// PromptFunc asks the user for the passphrase of the key at path.
+type PromptFunc func(path string) (string, error)
+
+// FromConfig returns a Chain of providers using cfg. The chain consists of ssh-agent, SSH key files, environment
+// variables, a credentials file, and finally Git credential helpers. If prompt is nil, encrypted keys are skipped.
+func FromConfig(cfg config.CredentialConfig, prompt PromptFunc) Chain {
+	var chain Chain
+	if !cfg.DisableAgent {
+		chain = append(chain, Agent{})
+	}
+
+	keys := cfg.SSHKeys
+	if len(keys) == 0 {
+		keys = DefaultSSHKeys
+	}
+	chain = append(chain, KeyFiles{Paths: keys, Prompt: prompt})
+
+	chain = append(chain, Env{})
+
+	file := cfg.File
+	if len(file) == 0 {
+		file = DefaultFile
+	}
+	chain = append(chain, File{Path: file})
+
+	for _, helper := range cfg.Helpers {
+		chain = append(chain, Helper{Command: helper})
+	}
+	return chain
+}
+
+// Default returns the Chain used when no configuration is given.
+func Default() Chain {
+	return FromConfig(config.CredentialConfig{}, nil)
+}
+
+// Iterator returns the credentials for a request one at a time. Providers in a Chain are only consulted once the
+// credentials of the previous providers have been used.
+type Iterator struct {
+	req       Request
+	providers []Provider
+	pending   []Credential
+	err       error
+}
+
+// NewIterator returns an Iterator over the credentials of p for req.
+func NewIterator(p Provider, req Request) *Iterator {
+	providers := []Provider{p}
+	if chain, ok := p.(Chain); ok {
+		providers = chain
+	}
+
+	return &Iterator{
+		req:       req,
+		providers: providers,
+	}
+}
+
+// Next returns the next credential. False is returned once all credentials have been used.
+func (it *Iterator) Next() (Credential, bool) {
+	for len(it.pending) == 0 {
+		if len(it.providers) == 0 {
+			return Credential{}, false
+		}
+
+		creds, err := it.providers[0].Credentials(it.req)
+		it.providers = it.providers[1:]
+		if err != nil {
+			// try remaining providers, report error if none succeed
+			it.err = err
+			continue
+		}
+		it.pending = creds
+	}
+
+	cred := it.pending[0]
+	it.pending = it.pending[1:]
+	return cred, true
+}
+
+// Err returns the last error encountered while retrieving credentials.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// isHTTP returns true if rawurl uses HTTP or HTTPS.
+func isHTTP(rawurl string) bool {
+	return strings.HasPrefix(rawurl, "https://") || strings.HasPrefix(rawurl, "http://")
+}
+
+// parseHTTP parses an HTTP(S) URL. An error is returned for other URLs.
+func parseHTTP(rawurl string) (*url.URL, error) {
+	if !isHTTP(rawurl) {
+		return nil, fmt.Errorf("'%s' is not an HTTP URL", rawurl)
+	}
+	return url.Parse(rawurl)
+}
+
+// expand expands '~' to the home directory in path.
+func expand(path string) string {
+	if expanded, err := homedir.Expand(path); err == nil {
+		return expanded
+	}
+	return path
+}
@@ -0,0 +1,90 @@
+package credentials
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Helper provides credentials for HTTPS remotes using a Git credential helper.
+type Helper struct {
+	// Command is specified the same way as Git's credential.helper option: the name of a helper (e.g. "osxkeychain"
+	// runs git credential-osxkeychain), an absolute path, or a shell command prefixed with "!".
+	Command string
+}
+
+// Credentials runs the helper's "get" action for req if it is for an HTTP remote.
+func (h Helper) Credentials(req Request) ([]Credential, error) {
+	remote, err := parseHTTP(req.URL)
+	if err != nil || len(h.Command) == 0 {
+		return nil, nil
+	}
+
+	var in bytes.Buffer
+	fmt.Fprintf(&in, "protocol=%s\n", remote.Scheme)
+	fmt.Fprintf(&in, "host=%s\n", remote.Host)
+	if path := strings.Trim(remote.Path, "/"); len(path) != 0 {
+		fmt.Fprintf(&in, "path=%s\n", path)
+	}
+	if len(req.Username) != 0 {
+		fmt.Fprintf(&in, "username=%s\n", req.Username)
+	}
+	in.WriteString("\n")
+
+	cmd := exec.Command("sh", "-c", h.commandLine()+" get")
+	cmd.Stdin = &in
+	cmd.Stderr = os.Stderr
+	// helpers must not prompt on the terminal when invoked by git
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("credential helper '%s' failed: %v", h.Command, err)
+	}
+
+	attrs := parseHelperOutput(out)
+	password, ok := attrs["password"]
+	if !ok {
+		return nil, nil
+	}
+
+	username := attrs["username"]
+	if len(username) == 0 {
+		username = httpUser(req)
+	}
+
+	return []Credential{{
+		Kind:     UserPass,
+		Username: username,
+		Password: password,
+		Source:   "credential helper " + h.Command,
+	}}, nil
+}
+
+// commandLine returns the shell command used to run the helper.
+func (h Helper) commandLine() string {
+	switch {
+	case strings.HasPrefix(h.Command, "!"):
+		return h.Command[1:]
+	case filepath.IsAbs(h.Command):
+		return h.Command
+	}
+	return "git credential-" + h.Command
+}
+
+// parseHelperOutput parses the key=value lines written by a credential helper.
+func parseHelperOutput(out []byte) map[string]string {
+	attrs := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "="); i > 0 {
+			attrs[line[:i]] = line[i+1:]
+		}
+	}
+	return attrs
+}
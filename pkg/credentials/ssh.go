@@ -0,0 +1,124 @@
+package credentials
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+const (
+	// AgentSocketEnv is the environment variable holding the location of the ssh-agent socket.
+	AgentSocketEnv = "SSH_AUTH_SOCK"
+
+	// defaultSSHUser is used when an SSH URL doesn't specify a user.
+	defaultSSHUser = "git"
+
+	opensshKeyMagic = "openssh-key-v1\x00"
+)
+
+// Agent provides the keys held by ssh-agent.
+type Agent struct{}
+
+// Credentials returns a credential using ssh-agent if an agent is running and req isn't for an HTTP remote.
+func (Agent) Credentials(req Request) ([]Credential, error) {
+	if isHTTP(req.URL) || len(os.Getenv(AgentSocketEnv)) == 0 {
+		return nil, nil
+	}
+
+	return []Credential{{
+		Kind:     SSHKey,
+		Username: sshUser(req),
+		Agent:    true,
+		Source:   "ssh-agent",
+	}}, nil
+}
+
+// KeyFiles provides SSH key pairs stored on disk. The public key of each pair must be stored next to the private
+// key with the suffix ".pub".
+type KeyFiles struct {
+	// Paths are the locations of private keys. '~' may be used to denote the home directory.
+	Paths []string
+	// Prompt is used to ask for the passphrase of encrypted keys. If nil, encrypted keys are skipped.
+	Prompt PromptFunc
+}
+
+// Credentials returns a credential for each key pair that exists if req isn't for an HTTP remote.
+func (k KeyFiles) Credentials(req Request) ([]Credential, error) {
+	if isHTTP(req.URL) {
+		return nil, nil
+	}
+
+	var creds []Credential
+	for _, path := range k.Paths {
+		privKey := expand(path)
+		pubKey := privKey + ".pub"
+		if _, err := os.Stat(pubKey); err != nil {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(privKey)
+		if err != nil {
+			continue
+		}
+
+		cred := Credential{
+			Kind:       SSHKey,
+			Username:   sshUser(req),
+			PublicKey:  pubKey,
+			PrivateKey: privKey,
+			Source:     privKey,
+		}
+
+		if isEncryptedKey(data) {
+			if k.Prompt == nil {
+				continue
+			}
+
+			prompt, keyPath := k.Prompt, privKey
+			cred.Passphrase = func() (string, error) {
+				return prompt(keyPath)
+			}
+		}
+		creds = append(creds, cred)
+	}
+	return creds, nil
+}
+
+// sshUser returns the user to authenticate as for req.
+func sshUser(req Request) string {
+	if len(req.Username) != 0 {
+		return req.Username
+	}
+	return defaultSSHUser
+}
+
+// isEncryptedKey returns true if the PEM encoded private key in data requires a passphrase.
+func isEncryptedKey(data []byte) bool {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return false
+	}
+
+	// legacy PEM format
+	if strings.Contains(block.Headers["Proc-Type"], "ENCRYPTED") || block.Type == "ENCRYPTED PRIVATE KEY" {
+		return true
+	}
+
+	// OpenSSH format stores the cipher name after the magic string
+	if block.Type == "OPENSSH PRIVATE KEY" && bytes.HasPrefix(block.Bytes, []byte(opensshKeyMagic)) {
+		rest := block.Bytes[len(opensshKeyMagic):]
+		if len(rest) < 4 {
+			return false
+		}
+
+		n := binary.BigEndian.Uint32(rest)
+		if uint32(len(rest)-4) < n {
+			return false
+		}
+		return string(rest[4:4+n]) != "none"
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ghodss/yaml"
+
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+// ArgEnvPrefix is the prefix of environment variables holding arguments for parameters.
+const ArgEnvPrefix = "SPREAD_ARG_"
+
+// Arguments holds the arguments given for parameters, keyed by parameter name.
+type Arguments map[string][]*pb.Argument
+
+// Set parses raw as the arguments of the parameter name. Raw is interpreted as JSON; input that isn't valid JSON is
+// used as a string.
+func (a Arguments) Set(name, raw string) error {
+	args, err := ParseArguments(raw, true)
+	if err != nil {
+		return fmt.Errorf("could not parse argument for '%s': %v", name, err)
+	}
+	a[name] = args
+	return nil
+}
+
+// Merge copies the arguments of other into a. Arguments in other take precedence.
+func (a Arguments) Merge(other Arguments) {
+	for name, args := range other {
+		a[name] = args
+	}
+}
+
+// ParseArgumentFlag parses an argument in the form "name=value" into a.
+func (a Arguments) ParseArgumentFlag(in string) error {
+	parts := strings.SplitN(in, "=", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 {
+		return fmt.Errorf("argument '%s' must be in the form name=value", in)
+	}
+	return a.Set(parts[0], parts[1])
+}
+
+// ArgumentsFromFile reads arguments from a JSON or YAML file containing an object keyed by parameter name. Values may
+// be strings, numbers, booleans, or arrays of them for parameters with patterns.
+func ArgumentsFromFile(path string) (Arguments, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read arguments: %v", err)
+	}
+
+	var values map[string]interface{}
+	if err = yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("could not parse arguments in '%s': %v", path, err)
+	}
+
+	args := make(Arguments, len(values))
+	for name, val := range values {
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return nil, err
+		}
+
+		if args[name], err = ParseArguments(string(raw), false); err != nil {
+			return nil, fmt.Errorf("invalid argument for '%s' in '%s': %v", name, path, err)
+		}
+	}
+	return args, nil
+}
+
+// ArgumentsFromEnv returns arguments for the parameters in names that are set in the environment. The variable of a
+// parameter is given by ArgEnvName. Lookup is used to retrieve variables, typically os.LookupEnv.
+func ArgumentsFromEnv(names []string, lookup func(string) (string, bool)) (Arguments, error) {
+	args := Arguments{}
+	for _, name := range names {
+		if raw, ok := lookup(ArgEnvName(name)); ok {
+			if err := args.Set(name, raw); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return args, nil
+}
+
+// ArgEnvName returns the environment variable holding the argument for the parameter name. The name is upper cased and
+// characters other than letters and digits are replaced with underscores.
+func ArgEnvName(name string) string {
+	return ArgEnvPrefix + strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, name)
+}
+
+// ParameterNames returns the sorted names of the parameters in fields.
+func ParameterNames(fields map[string][]*pb.Field) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
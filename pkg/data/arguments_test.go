@@ -0,0 +1,112 @@
+package data
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+func TestParseArgumentFlag(t *testing.T) {
+	args := Arguments{}
+	valid := map[string]*pb.Argument{
+		"replicas=3":          numArg(3),
+		"image=nginx:1.9":     strArg("nginx:1.9"),
+		"debug=true":          boolArg(true),
+		`greeting=say "hi"`:   strArg(`say "hi"`),
+		"url=http://a.b/?x=1": strArg("http://a.b/?x=1"),
+	}
+
+	for in, expected := range valid {
+		args = Arguments{}
+		if err := args.ParseArgumentFlag(in); err != nil {
+			t.Errorf("could not parse '%s': %v", in, err)
+			continue
+		}
+
+		for _, parsed := range args {
+			if len(parsed) != 1 || parsed[0].String() != expected.String() {
+				t.Errorf("'%s': expected %v, got %v", in, expected, parsed)
+			}
+		}
+	}
+
+	for _, in := range []string{"replicas", "=3"} {
+		if err := args.ParseArgumentFlag(in); err == nil {
+			t.Errorf("'%s' should not parse", in)
+		}
+	}
+}
+
+func TestArgumentsFromFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "spread-args")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	content := `
+replicas: 3
+image: nginx
+debug: false
+hosts: [a.example.com, b.example.com]
+`
+	if _, err = f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	args, err := ArgumentsFromFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]*pb.Argument{
+		"replicas": {numArg(3)},
+		"image":    {strArg("nginx")},
+		"debug":    {boolArg(false)},
+		"hosts":    {strArg("a.example.com"), strArg("b.example.com")},
+	}
+
+	if len(args) != len(expected) {
+		t.Fatalf("expected %d arguments, got %d", len(expected), len(args))
+	}
+
+	for name, vals := range expected {
+		if len(args[name]) != len(vals) {
+			t.Errorf("'%s': expected %v, got %v", name, vals, args[name])
+			continue
+		}
+
+		for i := range vals {
+			if args[name][i].String() != vals[i].String() {
+				t.Errorf("'%s': expected %v, got %v", name, vals[i], args[name][i])
+			}
+		}
+	}
+}
+
+func TestArgumentsFromEnv(t *testing.T) {
+	env := map[string]string{
+		"SPREAD_ARG_DB_HOST":  "db.internal",
+		"SPREAD_ARG_REPLICAS": "2",
+	}
+	lookup := func(key string) (string, bool) {
+		val, ok := env[key]
+		return val, ok
+	}
+
+	args, err := ArgumentsFromEnv([]string{"db-host", "replicas", "missing"}, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(args) != 2 {
+		t.Fatalf("expected 2 arguments, got %v", args)
+	} else if args["db-host"][0].GetStr() != "db.internal" {
+		t.Errorf("unexpected argument for db-host: %v", args["db-host"])
+	} else if args["replicas"][0].GetNumber() != 2 {
+		t.Errorf("unexpected argument for replicas: %v", args["replicas"])
+	}
+}
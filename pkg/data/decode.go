@@ -24,7 +24,7 @@ func decodeField(field *pb.Field) (interface{}, error) {
 	case *pb.Field_Array:
 		return decodeArray(v.Array.GetItems())
 	case *pb.Field_Link:
-		// TODO: IMPLEMENT FOLLOWING LINKS
+		// links are replaced by their targets with ResolveLinks before documents are exported or deployed
 		return nil, nil
 	}
 
@@ -1,8 +1,10 @@
 package data
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	pb "rsprd.com/spread/pkg/spreadproto"
 )
@@ -24,6 +26,115 @@ func ResolveRelativeField(field *pb.Field, fieldpath string) (resolvedField *pb.
 	return ResolveRelativeField(resolvedField, next)
 }
 
+// SetField sets the field at fieldpath relative to field to value, replacing any existing field. Missing objects and
+// arrays along the path are created. An array index may be at most the length of the array, which appends value.
+func SetField(field *pb.Field, fieldpath string, value *pb.Field) error {
+	fieldKey, arrIndex, next := nextField(fieldpath)
+	if len(fieldKey) == 0 && arrIndex < 0 {
+		return fmt.Errorf("could not resolve fieldpath '%s'", fieldpath)
+	}
+
+	if len(next) == 0 {
+		return putField(field, fieldKey, arrIndex, value)
+	}
+
+	child, err := childField(field, fieldKey, arrIndex)
+	if err != nil {
+		return err
+	} else if child == nil {
+		// create the container expected by the remainder of the path
+		if _, nextIndex, _ := nextField(next); nextIndex >= 0 {
+			child = &pb.Field{Value: &pb.Field_Array{Array: &pb.Array{}}}
+		} else {
+			child = &pb.Field{Value: &pb.Field_Object{Object: &pb.Object{Items: map[string]*pb.Field{}}}}
+		}
+
+		if err = putField(field, fieldKey, arrIndex, child); err != nil {
+			return err
+		}
+	}
+	return SetField(child, next, value)
+}
+
+// UnsetField removes the field at fieldpath relative to field. Removing an item from an array shifts the items after it.
+func UnsetField(field *pb.Field, fieldpath string) error {
+	parentPath, fieldKey, arrIndex := splitFieldPath(fieldpath)
+	if len(fieldKey) == 0 && arrIndex < 0 {
+		return fmt.Errorf("could not resolve fieldpath '%s'", fieldpath)
+	}
+
+	parent := field
+	if len(parentPath) != 0 {
+		var err error
+		if parent, err = ResolveRelativeField(field, parentPath); err != nil {
+			return err
+		}
+	}
+
+	if arrIndex >= 0 {
+		items := parent.GetArray().GetItems()
+		if _, err := getFromArrayField(parent, arrIndex); err != nil {
+			return err
+		}
+
+		items = append(items[:arrIndex], items[arrIndex+1:]...)
+		for i := arrIndex; i < len(items); i++ {
+			items[i].Key = strconv.Itoa(i)
+		}
+		parent.GetArray().Items = items
+		return nil
+	}
+
+	if _, err := getFromMapField(parent, fieldKey); err != nil {
+		return err
+	}
+	delete(parent.GetObject().GetItems(), fieldKey)
+	return nil
+}
+
+// FindParamOrLink returns the first field within field, including field itself, which has a parameter or is a link,
+// along with its path. Such fields are lost when field is replaced or removed. fieldpath is the path of field, which the
+// returned path extends. A nil field is returned if there are none.
+func FindParamOrLink(field *pb.Field, fieldpath string) (string, *pb.Field) {
+	if field.GetParam() != nil || field.GetLink() != nil {
+		return fieldpath, field
+	}
+
+	switch val := field.GetValue().(type) {
+	case *pb.Field_Object:
+		items := val.Object.GetItems()
+		for _, key := range objectKeys(items) {
+			subpath := key
+			// keys following an array index aren't delimited
+			if len(fieldpath) != 0 && !strings.HasSuffix(fieldpath, ")") {
+				subpath = fieldpath + "." + key
+			} else if len(fieldpath) != 0 {
+				subpath = fieldpath + key
+			}
+
+			if path, found := FindParamOrLink(items[key], subpath); found != nil {
+				return path, found
+			}
+		}
+	case *pb.Field_Array:
+		for i, item := range val.Array.GetItems() {
+			if path, found := FindParamOrLink(item, fmt.Sprintf("%s(%d)", fieldpath, i)); found != nil {
+				return path, found
+			}
+		}
+	}
+	return "", nil
+}
+
+// FieldFromJSON creates a field named key with the value described by the JSON in raw.
+func FieldFromJSON(key, raw string) (*pb.Field, error) {
+	var val interface{}
+	if err := json.Unmarshal([]byte(raw), &val); err != nil {
+		return nil, fmt.Errorf("could not parse '%s' as JSON: %v", raw, err)
+	}
+	return buildField(key, val)
+}
+
 // FieldValueEquals returns true if the value of the given fields is the same.
 func FieldValueEquals(this, other *pb.Field) bool {
 	// check for pointer + primitive  matches and nil values
@@ -152,7 +263,7 @@ func nextField(fieldpath string) (field string, array int, next string) {
 			return
 		} else if c == ')' {
 			indexStr := fieldpath[1:i]
-			if len(fieldpath) > i+2 {
+			if len(fieldpath) > i+1 {
 				next = fieldpath[i+1:]
 			}
 
@@ -167,3 +278,79 @@ func nextField(fieldpath string) (field string, array int, next string) {
 	}
 	return
 }
+
+// childField returns the existing field at key or index of field. Nil is returned if field is an object without key or
+// an array with exactly index items, so the child can be created.
+func childField(field *pb.Field, key string, index int) (*pb.Field, error) {
+	if index >= 0 {
+		if field.GetValue() == nil || len(field.GetArray().GetItems()) == index {
+			return nil, nil
+		}
+		return getFromArrayField(field, index)
+	}
+
+	if field.GetValue() == nil {
+		return nil, nil
+	} else if _, isObj := field.GetValue().(*pb.Field_Object); isObj {
+		if _, ok := field.GetObject().GetItems()[key]; !ok {
+			return nil, nil
+		}
+	}
+	return getFromMapField(field, key)
+}
+
+// putField stores value at key or index of field. Fields without a value become an object or array as needed.
+func putField(field *pb.Field, key string, index int, value *pb.Field) error {
+	if index >= 0 {
+		if field.GetValue() == nil {
+			field.Value = &pb.Field_Array{Array: &pb.Array{}}
+		}
+
+		arr := field.GetArray()
+		if arr == nil {
+			return fmt.Errorf("field '%s' isn't an array, cannot set %s[%d]", field.Key, field.Key, index)
+		} else if index > len(arr.Items) {
+			return fmt.Errorf("could not set %s[%d], the size of '%s' is %d", field.Key, index, field.Key, len(arr.Items))
+		}
+
+		value.Key = strconv.Itoa(index)
+		if index == len(arr.Items) {
+			arr.Items = append(arr.Items, value)
+		} else {
+			arr.Items[index] = value
+		}
+		return nil
+	}
+
+	if field.GetValue() == nil {
+		field.Value = &pb.Field_Object{Object: &pb.Object{}}
+	}
+
+	obj := field.GetObject()
+	if obj == nil {
+		return fmt.Errorf("field '%s' isn't an object, cannot set %s['%s']", field.Key, field.Key, key)
+	} else if obj.Items == nil {
+		obj.Items = map[string]*pb.Field{}
+	}
+
+	value.Key = key
+	obj.Items[key] = value
+	return nil
+}
+
+// splitFieldPath returns the path to the parent of the last field in fieldpath, and the key or index of that field.
+func splitFieldPath(fieldpath string) (parent, key string, index int) {
+	index = -1
+	for next := fieldpath; len(next) != 0; {
+		var rest string
+		key, index, rest = nextField(next)
+		if len(key) == 0 && index < 0 {
+			return
+		} else if len(rest) != 0 {
+			parent = fieldpath[:len(fieldpath)-len(rest)]
+		}
+		next = rest
+	}
+	parent = strings.TrimSuffix(parent, ".")
+	return
+}
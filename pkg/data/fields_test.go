@@ -1,7 +1,10 @@
 package data
 
 import (
+	"encoding/json"
 	"testing"
+
+	pb "rsprd.com/spread/pkg/spreadproto"
 )
 
 type NextFieldTest struct {
@@ -35,6 +38,12 @@ var nextFieldTests = []NextFieldTest{
 			{name: "cheese", array: -1},
 		},
 	},
+	{
+		"ports(0)a",
+		[]NextField{
+			{name: "ports", array: -1}, {array: 0}, {name: "a", array: -1},
+		},
+	},
 	// invalid (two dots + dot at beginning)
 	{
 		FieldStr: "..spec.template.spec.containers(0)",
@@ -70,3 +79,139 @@ func TestNextField(t *testing.T) {
 		}
 	}
 }
+
+var setFieldTests = []struct {
+	fieldpath string
+	value     string
+	expected  string
+	invalid   bool
+}{
+	{"kind", `"Service"`, `{"kind":"Service","spec":{"ports":[{"port":80}],"replicas":1}}`, false},
+	{"spec.replicas", `3`, `{"kind":"Pod","spec":{"ports":[{"port":80}],"replicas":3}}`, false},
+	{"spec.ports(0)port", `8080`, `{"kind":"Pod","spec":{"ports":[{"port":8080}],"replicas":1}}`, false},
+	{"spec.ports(1)", `{"port":443}`, `{"kind":"Pod","spec":{"ports":[{"port":80},{"port":443}],"replicas":1}}`, false},
+	{"metadata.labels.app", `"web"`, `{"kind":"Pod","metadata":{"labels":{"app":"web"}},"spec":{"ports":[{"port":80}],"replicas":1}}`, false},
+	{"spec.volumes(0)name", `"data"`, `{"kind":"Pod","spec":{"ports":[{"port":80}],"replicas":1,"volumes":[{"name":"data"}]}}`, false},
+	// index past end of array
+	{"spec.ports(2)", `{}`, ``, true},
+	// not an object
+	{"kind.name", `"a"`, ``, true},
+	// not an array
+	{"spec(0)", `1`, ``, true},
+}
+
+func TestSetField(t *testing.T) {
+	for i, test := range setFieldTests {
+		root := testFieldFromJSON(t, `{"kind":"Pod","spec":{"replicas":1,"ports":[{"port":80}]}}`)
+		value := testFieldFromJSON(t, test.value)
+
+		err := SetField(root, test.fieldpath, value)
+		if test.invalid {
+			if err == nil {
+				t.Errorf("test %d: setting '%s' should have failed", i, test.fieldpath)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("test %d: %v", i, err)
+			continue
+		}
+
+		if actual := testFieldJSON(t, root); actual != test.expected {
+			t.Errorf("test %d: expected %s, got %s", i, test.expected, actual)
+		}
+	}
+}
+
+func TestUnsetField(t *testing.T) {
+	root := testFieldFromJSON(t, `{"kind":"Pod","spec":{"replicas":1,"ports":[{"port":80},{"port":443},{"port":8080}]}}`)
+
+	if err := UnsetField(root, "spec.ports(0)"); err != nil {
+		t.Fatal(err)
+	} else if err = UnsetField(root, "spec.replicas"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"kind":"Pod","spec":{"ports":[{"port":443},{"port":8080}]}}`
+	if actual := testFieldJSON(t, root); actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+
+	// keys of shifted items should match their index
+	port, err := ResolveRelativeField(root, "spec.ports(1)")
+	if err != nil {
+		t.Fatal(err)
+	} else if port.Key != "1" {
+		t.Errorf("expected key '1', got '%s'", port.Key)
+	}
+
+	for _, fieldpath := range []string{"spec.replicas", "spec.ports(2)", "metadata.name", "kind.name"} {
+		if err = UnsetField(root, fieldpath); err == nil {
+			t.Errorf("unsetting '%s' should have failed", fieldpath)
+		}
+	}
+}
+
+func TestFindParamOrLink(t *testing.T) {
+	doc := &pb.Document{
+		Info: &pb.DocumentInfo{Path: "namespaces/default/pod/web"},
+		Root: testFieldFromJSON(t, `{"spec":{"replicas":1,"containers":[{"image":"nginx","ports":[{"port":80}]}]}}`),
+	}
+
+	if path, found := FindParamOrLink(doc.GetRoot(), ""); found != nil {
+		t.Errorf("expected no fields, found '%s'", path)
+	}
+
+	target, err := ParseSRI("*/namespaces/default/pod/web?spec.containers(0)image")
+	if err != nil {
+		t.Fatal(err)
+	} else if err = AddParamToDoc(doc, target, &pb.Parameter{Name: "image"}); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := GetFieldFromDocument(doc, "spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path, found := FindParamOrLink(spec, "spec")
+	if found == nil || found.GetParam() == nil || found.GetParam().Name != "image" {
+		t.Errorf("expected to find parameter 'image', found %v", found)
+	} else if path != "spec.containers(0)image" {
+		t.Errorf("expected path 'spec.containers(0)image', got '%s'", path)
+	}
+
+	ports, err := GetFieldFromDocument(doc, "spec.containers(0)ports")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	link := NewLink("", &SRI{Treeish: "*", Path: "namespaces/default/service/web", Field: "spec.ports(0)port"}, false)
+	ports.GetArray().GetItems()[0].GetObject().GetItems()["port"].Value = &pb.Field_Link{Link: link}
+
+	if path, found = FindParamOrLink(ports, "spec.containers(0)ports"); found == nil || found.GetLink() == nil {
+		t.Errorf("expected to find link, found %v", found)
+	} else if path != "spec.containers(0)ports(0)port" {
+		t.Errorf("expected path 'spec.containers(0)ports(0)port', got '%s'", path)
+	}
+}
+
+func testFieldFromJSON(t *testing.T, raw string) *pb.Field {
+	field, err := FieldFromJSON("", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return field
+}
+
+func testFieldJSON(t *testing.T, field *pb.Field) string {
+	val, err := decodeField(field)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(val)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
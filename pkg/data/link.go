@@ -2,10 +2,16 @@ package data
 
 import (
 	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
 
 	pb "rsprd.com/spread/pkg/spreadproto"
 )
 
+// MaxLinkDepth is the number of links that will be followed to resolve a single link.
+const MaxLinkDepth = 32
+
 // NewLink creates a new link from with the given details.
 func NewLink(packageName string, target *SRI, override bool) *pb.Link {
 	return &pb.Link{
@@ -31,3 +37,87 @@ func CreateLinkInDocument(doc *pb.Document, target *pb.Link, source *SRI) error
 	}
 	return nil
 }
+
+// ResolveLinks replaces the value of each link in docs with the value of its target. Relative targets are resolved
+// against docs, others are retrieved using lookup. If lookup is nil, only relative targets can be used. Targets which
+// are or contain links are resolved as well.
+func ResolveLinks(docs map[string]*pb.Document, lookup func(sri *SRI) (*pb.Document, error)) error {
+	for _, path := range documentPaths(docs) {
+		if err := resolveFieldLinks(docs[path].GetRoot(), docs, lookup, 0); err != nil {
+			return fmt.Errorf("could not resolve links of '%s': %v", path, err)
+		}
+	}
+	return nil
+}
+
+// resolveFieldLinks resolves the links in field and its children. Depth is the number of links already followed.
+func resolveFieldLinks(field *pb.Field, docs map[string]*pb.Document, lookup func(sri *SRI) (*pb.Document, error),
+	depth int) error {
+	switch val := field.GetValue().(type) {
+	case *pb.Field_Object:
+		for _, key := range objectKeys(val.Object.GetItems()) {
+			if err := resolveFieldLinks(val.Object.Items[key], docs, lookup, depth); err != nil {
+				return err
+			}
+		}
+	case *pb.Field_Array:
+		for _, item := range val.Array.GetItems() {
+			if err := resolveFieldLinks(item, docs, lookup, depth); err != nil {
+				return err
+			}
+		}
+	case *pb.Field_Link:
+		target, err := linkTarget(val.Link, docs, lookup, depth)
+		if err != nil {
+			return err
+		}
+		field.Value = target.Value
+	}
+	return nil
+}
+
+// linkTarget returns a resolved copy of the field link points to.
+func linkTarget(link *pb.Link, docs map[string]*pb.Document, lookup func(sri *SRI) (*pb.Document, error),
+	depth int) (*pb.Field, error) {
+	if depth >= MaxLinkDepth {
+		return nil, ErrLinkDepth
+	} else if link.GetTarget() == nil {
+		return nil, errors.New("link does not have a target")
+	} else if len(link.Args) != 0 {
+		return nil, errors.New("arguments for links are not supported")
+	}
+
+	target := link.GetTarget()
+	sri := &SRI{Treeish: target.Treeish, Path: target.Path, Field: target.Field}
+
+	var doc *pb.Document
+	var err error
+	if sri.Treeish == "*" {
+		var ok bool
+		if doc, ok = docs[sri.Path]; !ok {
+			return nil, fmt.Errorf("link target '%s' does not exist", sri)
+		}
+	} else if lookup == nil {
+		return nil, fmt.Errorf("link target '%s' must be relative", sri)
+	} else if doc, err = lookup(sri); err != nil {
+		return nil, fmt.Errorf("could not retrieve link target '%s': %v", sri, err)
+	}
+
+	field := doc.GetRoot()
+	if sri.IsField() {
+		if field, err = GetFieldFromDocument(doc, sri.Field); err != nil {
+			return nil, err
+		}
+	}
+
+	field = proto.Clone(field).(*pb.Field)
+	if err = resolveFieldLinks(field, docs, lookup, depth+1); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+var (
+	// ErrLinkDepth is returned when a link can't be resolved within MaxLinkDepth steps, usually due to a cycle.
+	ErrLinkDepth = fmt.Errorf("links nested more than %d deep, they may form a cycle", MaxLinkDepth)
+)
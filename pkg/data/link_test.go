@@ -0,0 +1,79 @@
+package data
+
+import (
+	"testing"
+
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+func linkTestDoc(t *testing.T, path, raw string) *pb.Document {
+	root, err := FieldFromJSON("", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &pb.Document{Name: path, Info: &pb.DocumentInfo{Path: path}, Root: root}
+}
+
+func linkTestField(t *testing.T, doc *pb.Document, target string) {
+	sri, err := ParseSRI(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := &SRI{Treeish: "*", Path: doc.Info.Path, Field: "link"}
+	if err = CreateLinkInDocument(doc, NewLink("", sri, false), source); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveLinks(t *testing.T) {
+	db := linkTestDoc(t, "namespaces/default/service/db", `{"spec":{"clusterIP":"10.0.0.1","ports":[{"port":5432}]}}`)
+	web := linkTestDoc(t, "namespaces/default/pod/web", `{"link":null}`)
+	chained := linkTestDoc(t, "namespaces/default/pod/chained", `{"link":null}`)
+	other := linkTestDoc(t, "namespaces/default/pod/other", `{"link":null}`)
+
+	linkTestField(t, web, "*/namespaces/default/service/db?spec.ports(0)port")
+	linkTestField(t, chained, "*/namespaces/default/pod/web?link")
+	linkTestField(t, other, "abcdef1/namespaces/default/service/db?spec.clusterIP")
+
+	docs := map[string]*pb.Document{db.Info.Path: db, web.Info.Path: web, chained.Info.Path: chained, other.Info.Path: other}
+	if err := ResolveLinks(docs, nil); err == nil {
+		t.Error("links to other commits shouldn't resolve without lookup")
+	}
+
+	lookup := func(sri *SRI) (*pb.Document, error) {
+		return linkTestDoc(t, sri.Path, `{"spec":{"clusterIP":"10.0.0.2"}}`), nil
+	}
+	if err := ResolveLinks(docs, lookup); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[*pb.Document]string{
+		web:     `{"link":5432}`,
+		chained: `{"link":5432}`,
+		other:   `{"link":"10.0.0.2"}`,
+	}
+	for doc, json := range expected {
+		if actual := testFieldJSON(t, doc.GetRoot()); actual != json {
+			t.Errorf("%s: expected %s, got %s", doc.Info.Path, json, actual)
+		}
+	}
+}
+
+func TestResolveLinksCycle(t *testing.T) {
+	a := linkTestDoc(t, "namespaces/default/pod/a", `{"link":null}`)
+	b := linkTestDoc(t, "namespaces/default/pod/b", `{"link":null}`)
+	linkTestField(t, a, "*/namespaces/default/pod/b?link")
+	linkTestField(t, b, "*/namespaces/default/pod/a?link")
+
+	docs := map[string]*pb.Document{a.Info.Path: a, b.Info.Path: b}
+	if err := ResolveLinks(docs, nil); err == nil {
+		t.Error("links forming a cycle should not resolve")
+	}
+
+	missing := linkTestDoc(t, "namespaces/default/pod/missing", `{"link":null}`)
+	linkTestField(t, missing, "*/namespaces/default/service/none?spec")
+	if err := ResolveLinks(map[string]*pb.Document{missing.Info.Path: missing}, nil); err == nil {
+		t.Error("links to missing documents should not resolve")
+	}
+}
@@ -6,14 +6,30 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
 	pb "rsprd.com/spread/pkg/spreadproto"
 )
 
+// SecretMask is displayed in place of the values of fields with secret parameters.
+const SecretMask = "********"
+
 // InteractiveArgs hosts an interactive session using a Reader and Writer which prompts for input which is used to
 // populate the provided field. If required is true then only fields without defaults will be prompted for.
 func InteractiveArgs(r io.ReadCloser, w io.Writer, field *pb.Field, required bool) error {
+	return InteractiveSharedArgs(r, w, []*pb.Field{field}, required)
+}
+
+// InteractiveSharedArgs prompts once for a parameter shared by fields and applies the input to each of them. If no
+// input is given, each field uses its own default. If required is true then the prompt is skipped if the parameter has
+// a default.
+func InteractiveSharedArgs(r io.ReadCloser, w io.Writer, fields []*pb.Field, required bool) error {
+	if len(fields) == 0 {
+		return errors.New("no fields were given")
+	}
+
+	field := fields[0]
 	param := field.GetParam()
 
 	defaultVal := param.GetDefault()
@@ -24,24 +40,94 @@ func InteractiveArgs(r io.ReadCloser, w io.Writer, field *pb.Field, required boo
 
 	fmt.Fprintln(w, "Name: ", param.Name)
 	fmt.Fprintln(w, "Prompt: ", param.Prompt)
-	fmt.Fprint(w, "Input: ", displayDefault(defaultVal))
+	if desc := DescribeParameter(param); len(desc) != 0 {
+		fmt.Fprintln(w, "Type: ", desc)
+	}
+
 	reader := bufio.NewReader(r)
-	text, err := reader.ReadString('\n')
-	if err != nil {
+	for {
+		fmt.Fprint(w, "Input: ", displayDefault(defaultVal))
+		text, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+
+		// use defaults if no input given
+		var args []*pb.Argument
+		if len(text) > 1 {
+			// typed parameters convert string input themselves
+			_, str := field.GetValue().(*pb.Field_Str)
+			args, err = ParseArguments(text[:len(text)-1], str || param.Type != pb.ParameterType_ANY)
+			if err != nil {
+				return err
+			}
+		}
+
+		// prompt again if input doesn't satisfy the parameter
+		if err = checkSharedArguments(fields, args); err != nil {
+			fmt.Fprintln(w, err)
+			continue
+		}
+
+		return ApplySharedArguments(fields, args...)
+	}
+}
+
+// ApplySharedArguments applies args to each of fields, which share a parameter. Arguments are checked against every
+// field before any are applied. If no arguments are given, each field uses its own default.
+func ApplySharedArguments(fields []*pb.Field, args ...*pb.Argument) error {
+	if err := checkSharedArguments(fields, args); err != nil {
 		return err
 	}
 
-	// use default if no input given
-	args := []*pb.Argument{defaultVal}
-	if len(text) > 1 {
-		_, str := field.GetValue().(*pb.Field_Str)
-		args, err = ParseArguments(text[:len(text)-1], str)
-		if err != nil {
+	for _, field := range fields {
+		if err := ApplyArguments(field, args...); err != nil {
 			return err
 		}
 	}
+	return nil
+}
+
+// CheckSharedParameter returns an error if the parameters of fields, which share a name, accept different arguments or
+// are computed from different sources. Fields may use different patterns, prompts and defaults.
+func CheckSharedParameter(fields []*pb.Field) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	first := fields[0].GetParam()
+	for _, field := range fields[1:] {
+		param := field.GetParam()
+		if param.Source != first.Source {
+			return fmt.Errorf("fields with parameter '%s' have different sources", first.Name)
+		} else if param.Type != first.Type || param.Regex != first.Regex ||
+			param.GetMin().String() != first.GetMin().String() || param.GetMax().String() != first.GetMax().String() ||
+			strings.Join(param.Values, "\x00") != strings.Join(first.Values, "\x00") {
+			return fmt.Errorf("fields with parameter '%s' have different types or constraints", first.Name)
+		}
+	}
+	return nil
+}
 
-	return ApplyArguments(field, args...)
+// checkSharedArguments returns an error if args can't be applied to all fields.
+func checkSharedArguments(fields []*pb.Field, args []*pb.Argument) error {
+	for _, field := range fields {
+		param := field.GetParam()
+		if param == nil {
+			return fmt.Errorf("field %s does not have a parameter", field.Key)
+		}
+
+		if len(args) != 0 {
+			if err := checkArguments(param, args); err != nil {
+				return err
+			}
+		} else if param.GetDefault() == nil {
+			return fmt.Errorf("a value is required for '%s'", param.Name)
+		} else if _, err := CheckArgument(param, param.GetDefault()); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func displayDefault(d *pb.Argument) string {
@@ -60,7 +146,8 @@ func displayDefault(d *pb.Argument) string {
 	return fmt.Sprintf("(%v) ", out)
 }
 
-// AddParamToDoc adds the given parameter to the
+// AddParamToDoc adds the given parameter to the field of doc addressed by target. The value of fields with secret
+// parameters is cleared so it isn't committed.
 func AddParamToDoc(doc *pb.Document, target *SRI, param *pb.Parameter) error {
 	if !target.IsField() {
 		return errors.New("passed SRI is not a field")
@@ -72,12 +159,52 @@ func AddParamToDoc(doc *pb.Document, target *SRI, param *pb.Parameter) error {
 	}
 
 	field.Param = param
+	if IsSecretParameter(param) {
+		field.Value = &pb.Field_Str{}
+	}
+	return nil
+}
+
+// MaskSecrets replaces the values of field and its subfields that have secret parameters with SecretMask.
+func MaskSecrets(field *pb.Field) {
+	if IsSecretParameter(field.GetParam()) {
+		field.Value = &pb.Field_Str{Str: SecretMask}
+		return
+	}
+
+	switch val := field.GetValue().(type) {
+	case *pb.Field_Object:
+		for _, item := range val.Object.GetItems() {
+			MaskSecrets(item)
+		}
+	case *pb.Field_Array:
+		for _, item := range val.Array.GetItems() {
+			MaskSecrets(item)
+		}
+	}
+}
+
+// RemoveParamFromDoc removes the parameter of the field addressed by target. An error is returned if the field doesn't
+// have a parameter.
+func RemoveParamFromDoc(doc *pb.Document, target *SRI) error {
+	if !target.IsField() {
+		return errors.New("passed SRI is not a field")
+	}
+
+	field, err := GetFieldFromDocument(doc, target.Field)
+	if err != nil {
+		return err
+	} else if field.GetParam() == nil {
+		return fmt.Errorf("field '%s' does not have a parameter", target.Field)
+	}
+
+	field.Param = nil
 	return nil
 }
 
 // ApplyArguments takes the given arguments and uses them to satisfy a field parameter. If a single argument and no
-// formatting pattern are given the single argument is used as the field value. Otherwise the arguments will be used as
-// arguments to Printf with the formatting string as the pattern.
+// formatting pattern are given the single argument is used as the field value. Otherwise the arguments are formatted
+// using the pattern, which is either a template or a Printf format string.
 func ApplyArguments(field *pb.Field, args ...*pb.Argument) error {
 	if field == nil {
 		return errors.New("field was nil")
@@ -86,51 +213,129 @@ func ApplyArguments(field *pb.Field, args ...*pb.Argument) error {
 	} else if len(args) < 1 && field.GetParam().GetDefault() == nil {
 		return errors.New("an argument must be specified if no default is given")
 	} else if len(args) < 1 {
+		if _, err := CheckArgument(field.GetParam(), field.GetParam().GetDefault()); err != nil {
+			return err
+		}
 		return applyDefault(field)
-	} else if len(args) == 1 && len(field.GetParam().Pattern) == 0 {
+	}
+
+	args, err := convertArguments(field.GetParam(), args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 && len(field.GetParam().Pattern) == 0 {
 		return simpleArgApply(field, args[0])
 	} else if len(args) > 1 && len(field.GetParam().Pattern) == 0 {
 		return errors.New("may only use multiple arguments if a string template is provided")
 	}
 
-	argVals := make([]interface{}, len(args))
-	for i, v := range args {
-		switch val := v.GetValue().(type) {
-		case *pb.Argument_Number:
-			argVals[i] = val.Number
-		case *pb.Argument_Str:
-			argVals[i] = val.Str
-		case *pb.Argument_Boolean:
-			argVals[i] = val.Boolean
+	return applyPattern(field, args)
+}
+
+// convertArguments checks args against param, returning them converted to the type of param.
+func convertArguments(param *pb.Parameter, args []*pb.Argument) ([]*pb.Argument, error) {
+	converted := make([]*pb.Argument, len(args))
+	for i, arg := range args {
+		arg, err := CheckArgument(param, arg)
+		if err != nil {
+			return nil, err
 		}
+		converted[i] = arg
 	}
+	return converted, nil
+}
 
-	val := fmt.Sprintf(field.GetParam().Pattern, argVals...)
-	field.Value = &pb.Field_Str{Str: val}
-	return nil
+// checkArguments returns an error if any of args doesn't satisfy param.
+func checkArguments(param *pb.Parameter, args []*pb.Argument) error {
+	_, err := convertArguments(param, args)
+	return err
 }
 
-// ParameterFields returns the fields with parameters contained within a Document.
-func ParameterFields(docs map[string]*pb.Document) map[string]*pb.Field {
-	fields := map[string]*pb.Field{}
-	for _, doc := range docs {
-		AddParameterFields(doc.GetRoot(), fields)
+// ParameterFields returns the fields with parameters contained within Documents, keyed by parameter name. Fields
+// sharing a parameter name are grouped together.
+func ParameterFields(docs map[string]*pb.Document) map[string][]*pb.Field {
+	fields := map[string][]*pb.Field{}
+
+	// visit documents in order so fields are grouped deterministically
+	for _, path := range documentPaths(docs) {
+		AddParameterFields(docs[path].GetRoot(), fields)
 	}
 	return fields
 }
 
+// ParameterSRIs returns relative SRIs addressing the fields with parameters contained within Documents, keyed by
+// parameter name. The SRIs of a parameter are in the same order as its fields in ParameterFields.
+func ParameterSRIs(docs map[string]*pb.Document) map[string][]*SRI {
+	sris := map[string][]*SRI{}
+	for _, path := range documentPaths(docs) {
+		addParameterSRIs(docs[path].GetRoot(), path, "", sris)
+	}
+	return sris
+}
+
+func addParameterSRIs(field *pb.Field, path, fieldpath string, sris map[string][]*SRI) {
+	if param := field.GetParam(); param != nil {
+		sris[param.Name] = append(sris[param.Name], &SRI{
+			Treeish: "*",
+			Path:    path,
+			Field:   fieldpath,
+		})
+	}
+
+	switch val := field.GetValue().(type) {
+	case *pb.Field_Object:
+		items := val.Object.GetItems()
+		for _, key := range objectKeys(items) {
+			subpath := key
+			// keys following an array index aren't delimited
+			if len(fieldpath) != 0 && !strings.HasSuffix(fieldpath, ")") {
+				subpath = fieldpath + "." + key
+			} else if len(fieldpath) != 0 {
+				subpath = fieldpath + key
+			}
+			addParameterSRIs(items[key], path, subpath, sris)
+		}
+	case *pb.Field_Array:
+		for i, arrField := range val.Array.GetItems() {
+			addParameterSRIs(arrField, path, fmt.Sprintf("%s(%d)", fieldpath, i), sris)
+		}
+	}
+}
+
+// documentPaths returns the sorted paths of docs.
+func documentPaths(docs map[string]*pb.Document) []string {
+	paths := make([]string, 0, len(docs))
+	for path := range docs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// objectKeys returns the sorted keys of items.
+func objectKeys(items map[string]*pb.Field) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // AddParameterFields adds fields with parameters from the given field (and its subfields) to the map given. The name of the parameter is the key.
-func AddParameterFields(field *pb.Field, params map[string]*pb.Field) {
+func AddParameterFields(field *pb.Field, params map[string][]*pb.Field) {
 	param := field.GetParam()
 	// add to map if has parameter
 	if param != nil {
-		params[param.Name] = field
+		params[param.Name] = append(params[param.Name], field)
 	}
 
 	switch val := field.GetValue().(type) {
 	case *pb.Field_Object:
-		for _, objField := range val.Object.GetItems() {
-			AddParameterFields(objField, params)
+		items := val.Object.GetItems()
+		for _, key := range objectKeys(items) {
+			AddParameterFields(items[key], params)
 		}
 	case *pb.Field_Array:
 		for _, arrField := range val.Array.GetItems() {
@@ -146,8 +351,8 @@ func ParseArguments(in string, coerceStr bool) (args []*pb.Argument, err error)
 	if err != nil {
 		// if coercion is requested, attempt to process input as string
 		if strings.HasPrefix(err.Error(), "invalid character") && coerceStr {
-			in = fmt.Sprintf(`"%s"`, in)
-			args, err = ParseArguments(in, false)
+			quoted, _ := json.Marshal(in)
+			args, err = ParseArguments(string(quoted), false)
 		}
 		return
 	}
@@ -1,6 +1,9 @@
 package data
 
 import (
+	"bytes"
+	"io/ioutil"
+	"strings"
 	"testing"
 
 	pb "rsprd.com/spread/pkg/spreadproto"
@@ -124,3 +127,235 @@ func TestApplyArguments(t *testing.T) {
 		}
 	}
 }
+
+func sharedDocs(t *testing.T) map[string]*pb.Document {
+	rc := map[string]interface{}{
+		"image":  "nginx:1.9",
+		"labels": map[string]interface{}{"version": "1.9"},
+	}
+	doc, err := CreateDocument("web", "namespaces/default/replicationcontroller/web", rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	image, err := GetFieldFromDocument(doc, "image")
+	if err != nil {
+		t.Fatal(err)
+	}
+	image.Param = &pb.Parameter{Name: "version", Prompt: "nginx version", Pattern: "nginx:%s"}
+
+	label, err := GetFieldFromDocument(doc, "labels.version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	label.Param = &pb.Parameter{Name: "version", Default: strArg("1.9")}
+
+	return map[string]*pb.Document{doc.GetInfo().Path: doc}
+}
+
+func TestParameterFieldsShared(t *testing.T) {
+	fields := ParameterFields(sharedDocs(t))
+	if len(fields) != 1 {
+		t.Fatalf("expected a single parameter, got %d", len(fields))
+	} else if len(fields["version"]) != 2 {
+		t.Fatalf("expected parameter to be bound to 2 fields, got %d", len(fields["version"]))
+	}
+
+	// fields are ordered by key
+	if fields["version"][0].Key != "image" {
+		t.Errorf("expected first field to be 'image', got '%s'", fields["version"][0].Key)
+	}
+}
+
+func TestApplySharedArguments(t *testing.T) {
+	shared := ParameterFields(sharedDocs(t))["version"]
+	if err := ApplySharedArguments(shared); err == nil {
+		t.Error("defaults should be required for every field when no arguments are given")
+	}
+
+	if err := ApplySharedArguments(shared, strArg("1.10")); err != nil {
+		t.Fatal(err)
+	}
+
+	if image := shared[0].GetStr(); image != "nginx:1.10" {
+		t.Errorf("expected pattern to be applied to image, got '%s'", image)
+	}
+
+	if label := shared[1].GetStr(); label != "1.10" {
+		t.Errorf("expected argument to be applied to label, got '%s'", label)
+	}
+}
+
+func TestInteractiveSharedArgs(t *testing.T) {
+	shared := ParameterFields(sharedDocs(t))["version"]
+	in := ioutil.NopCloser(strings.NewReader("1.11-alpine\n"))
+	var out bytes.Buffer
+	if err := InteractiveSharedArgs(in, &out, shared, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Count(out.String(), "Name:") != 1 {
+		t.Errorf("expected a single prompt, got:\n%s", out.String())
+	}
+
+	if shared[0].GetStr() != "nginx:1.11-alpine" || shared[1].GetStr() != "1.11-alpine" {
+		t.Errorf("input should be applied to every field, got '%s' and '%s'", shared[0].GetStr(), shared[1].GetStr())
+	}
+}
+
+func TestCheckSharedParameter(t *testing.T) {
+	fields := []*pb.Field{
+		{Param: &pb.Parameter{Name: "port", Type: pb.ParameterType_PORT, Prompt: "port"}},
+		{Param: &pb.Parameter{Name: "port", Type: pb.ParameterType_PORT, Pattern: "%v"}},
+	}
+
+	if err := CheckSharedParameter(fields); err != nil {
+		t.Errorf("prompts and patterns may differ: %v", err)
+	}
+
+	fields = append(fields, &pb.Field{Param: &pb.Parameter{Name: "port", Type: pb.ParameterType_STRING}})
+	if err := CheckSharedParameter(fields); err == nil {
+		t.Error("different types should not be allowed")
+	}
+}
+
+func TestParameterSRIs(t *testing.T) {
+	docs := sharedDocs(t)
+	doc := docs["namespaces/default/replicationcontroller/web"]
+	doc.Root.GetObject().Items["ports"] = &pb.Field{
+		Key: "ports",
+		Value: &pb.Field_Array{Array: &pb.Array{Items: []*pb.Field{{
+			Value: &pb.Field_Object{Object: &pb.Object{Items: map[string]*pb.Field{
+				"port": {Key: "port", Value: &pb.Field_Number{Number: 80}, Param: &pb.Parameter{Name: "port"}},
+			}}},
+		}}}},
+	}
+
+	expected := map[string][]string{
+		"version": {
+			"*/namespaces/default/replicationcontroller/web?image",
+			"*/namespaces/default/replicationcontroller/web?labels.version",
+		},
+		"port": {"*/namespaces/default/replicationcontroller/web?ports(0)port"},
+	}
+
+	sris := ParameterSRIs(docs)
+	if len(sris) != len(expected) {
+		t.Fatalf("expected %d parameters, got %d", len(expected), len(sris))
+	}
+
+	for name, strs := range expected {
+		if len(sris[name]) != len(strs) {
+			t.Errorf("'%s': expected %v, got %v", name, strs, sris[name])
+			continue
+		}
+
+		for i, str := range strs {
+			if sris[name][i].String() != str {
+				t.Errorf("'%s': expected '%s', got '%s'", name, str, sris[name][i])
+			}
+
+			// SRIs must address the field with the parameter
+			field, err := GetFieldFromDocument(doc, sris[name][i].Field)
+			if err != nil {
+				t.Errorf("could not resolve '%s': %v", sris[name][i], err)
+			} else if field.GetParam() == nil || field.GetParam().Name != name {
+				t.Errorf("'%s' does not have parameter '%s'", sris[name][i], name)
+			}
+		}
+	}
+}
+
+func TestRemoveParamFromDoc(t *testing.T) {
+	docs := sharedDocs(t)
+	doc := docs["namespaces/default/replicationcontroller/web"]
+
+	target, err := ParseSRI("*/namespaces/default/replicationcontroller/web?image")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = RemoveParamFromDoc(doc, target); err != nil {
+		t.Fatal(err)
+	}
+
+	if fields := ParameterFields(docs)["version"]; len(fields) != 1 || fields[0].Key != "version" {
+		t.Errorf("expected only the label to have a parameter, got %v", fields)
+	}
+
+	if err = RemoveParamFromDoc(doc, target); err == nil {
+		t.Error("removing a parameter from a field without one should fail")
+	}
+
+	target.Field = ""
+	if err = RemoveParamFromDoc(doc, target); err == nil {
+		t.Error("SRIs must address a field")
+	}
+}
+
+func TestSecretParameters(t *testing.T) {
+	secret := map[string]interface{}{
+		"data": map[string]interface{}{"password": "aHVudGVyMg=="},
+	}
+	doc, err := CreateDocument("db", "namespaces/default/secret/db", secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target, err := ParseSRI("*/namespaces/default/secret/db?data.password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	param := &pb.Parameter{Name: "password", Type: pb.ParameterType_SECRET}
+	if err = AddParamToDoc(doc, target, param); err != nil {
+		t.Fatal(err)
+	}
+
+	field, err := GetFieldFromDocument(doc, target.Field)
+	if err != nil {
+		t.Fatal(err)
+	} else if field.GetStr() != "" {
+		t.Errorf("the value of secret fields should not be stored, got '%s'", field.GetStr())
+	}
+
+	if err = ApplyArguments(field, strArg("c2VjcmV0")); err != nil {
+		t.Fatal(err)
+	}
+
+	MaskSecrets(doc.GetRoot())
+	if field.GetStr() != SecretMask {
+		t.Errorf("expected secret to be masked, got '%s'", field.GetStr())
+	}
+}
+
+func TestSecretSourceParameters(t *testing.T) {
+	doc, err := CreateDocument("db", "namespaces/default/pod/db", map[string]interface{}{"token": "hunter2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target, err := ParseSRI("*/namespaces/default/pod/db?token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// parameters reading from the secret store are secret regardless of type
+	param := &pb.Parameter{Name: "token", Type: pb.ParameterType_STRING, Source: "secret:token"}
+	if err = AddParamToDoc(doc, target, param); err != nil {
+		t.Fatal(err)
+	}
+
+	field, err := GetFieldFromDocument(doc, target.Field)
+	if err != nil {
+		t.Fatal(err)
+	} else if field.GetStr() != "" {
+		t.Errorf("the value of fields with secret sources should not be stored, got '%s'", field.GetStr())
+	}
+
+	field.Value = &pb.Field_Str{Str: "hunter2"}
+	MaskSecrets(doc.GetRoot())
+	if field.GetStr() != SecretMask {
+		t.Errorf("expected secret to be masked, got '%s'", field.GetStr())
+	}
+}
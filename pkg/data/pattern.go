@@ -0,0 +1,159 @@
+package data
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+// templateStart marks a parameter pattern as a template. Patterns without it are Printf format strings.
+const templateStart = "{{"
+
+// PatternFuncs are the functions available to template patterns.
+var PatternFuncs = template.FuncMap{
+	"lower": func(v interface{}) string {
+		return strings.ToLower(toString(v))
+	},
+	"upper": func(v interface{}) string {
+		return strings.ToUpper(toString(v))
+	},
+	"base64": func(v interface{}) string {
+		return base64.StdEncoding.EncodeToString([]byte(toString(v)))
+	},
+	"join": func(sep string, items []interface{}) string {
+		strs := make([]string, len(items))
+		for i, item := range items {
+			strs[i] = toString(item)
+		}
+		return strings.Join(strs, sep)
+	},
+	"default": func(def, v interface{}) interface{} {
+		if v == nil || v == "" {
+			return def
+		}
+		return v
+	},
+}
+
+// patternData is the data available to template patterns.
+type patternData struct {
+	// Name is the name of the parameter.
+	Name string
+	// Value is the first argument.
+	Value interface{}
+	// Args are all arguments given for the parameter.
+	Args []interface{}
+}
+
+// IsTemplatePattern returns true if pattern is a template rather than a Printf format string.
+func IsTemplatePattern(pattern string) bool {
+	return strings.Contains(pattern, templateStart)
+}
+
+// ValidatePattern checks that a template pattern can be parsed. Printf patterns are always valid.
+func ValidatePattern(pattern string) error {
+	if !IsTemplatePattern(pattern) {
+		return nil
+	}
+
+	_, err := parsePattern(pattern)
+	return err
+}
+
+// applyPattern formats args using the pattern of field's parameter and sets the result as the value of field. The
+// output of templates is converted to the type of the field's current value, allowing numbers and booleans to be
+// produced. Printf patterns always produce strings.
+func applyPattern(field *pb.Field, args []*pb.Argument) error {
+	pattern := field.GetParam().Pattern
+	vals := make([]interface{}, len(args))
+	for i, arg := range args {
+		vals[i] = argValue(arg)
+	}
+
+	if !IsTemplatePattern(pattern) {
+		out := fmt.Sprintf(pattern, vals...)
+		if strings.Contains(out, "%!") && !strings.Contains(fmt.Sprint(vals...), "%!") {
+			return fmt.Errorf("arguments for '%s' don't match pattern '%s': %s", field.GetParam().Name, pattern, out)
+		}
+		field.Value = &pb.Field_Str{Str: out}
+		return nil
+	}
+
+	tmpl, err := parsePattern(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern for '%s': %v", field.GetParam().Name, err)
+	}
+
+	data := patternData{
+		Name: field.GetParam().Name,
+		Args: make([]interface{}, len(vals)),
+	}
+	for i, val := range vals {
+		// whole numbers are printed without exponents
+		if num, isNum := val.(float64); isNum && num == math.Trunc(num) && math.Abs(num) < 1<<53 {
+			val = int64(num)
+		}
+		data.Args[i] = val
+	}
+	if len(data.Args) != 0 {
+		data.Value = data.Args[0]
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("could not apply pattern for '%s': %v", field.GetParam().Name, err)
+	}
+
+	out := buf.String()
+	switch field.GetValue().(type) {
+	case *pb.Field_Number:
+		num, err := strconv.ParseFloat(strings.TrimSpace(out), 64)
+		if err != nil {
+			return fmt.Errorf("pattern for '%s' produced '%s', expected a number", field.GetParam().Name, out)
+		}
+		field.Value = &pb.Field_Number{Number: num}
+	case *pb.Field_Boolean:
+		b, err := strconv.ParseBool(strings.TrimSpace(out))
+		if err != nil {
+			return fmt.Errorf("pattern for '%s' produced '%s', expected true or false", field.GetParam().Name, out)
+		}
+		field.Value = &pb.Field_Boolean{Boolean: b}
+	default:
+		field.Value = &pb.Field_Str{Str: out}
+	}
+	return nil
+}
+
+func parsePattern(pattern string) (*template.Template, error) {
+	return template.New("pattern").Option("missingkey=error").Funcs(PatternFuncs).Parse(pattern)
+}
+
+// argValue returns the value of arg as a float64, string, or bool.
+func argValue(arg *pb.Argument) interface{} {
+	switch val := arg.GetValue().(type) {
+	case *pb.Argument_Number:
+		return val.Number
+	case *pb.Argument_Str:
+		return val.Str
+	case *pb.Argument_Boolean:
+		return val.Boolean
+	}
+	return nil
+}
+
+// toString formats v for use in templates. Whole numbers are formatted without a decimal point.
+func toString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return formatNumber(val)
+	}
+	return fmt.Sprint(v)
+}
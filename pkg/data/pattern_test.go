@@ -0,0 +1,113 @@
+package data
+
+import (
+	"testing"
+
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+func TestApplyPattern(t *testing.T) {
+	tests := []struct {
+		value   *pb.Field
+		pattern string
+		args    []*pb.Argument
+		out     *pb.Field
+		err     bool
+	}{
+		{
+			value:   &pb.Field{Value: &pb.Field_Str{}},
+			pattern: "nginx:%s",
+			args:    []*pb.Argument{strArg("1.9")},
+			out:     &pb.Field{Value: &pb.Field_Str{Str: "nginx:1.9"}},
+		},
+		{ // mismatched Printf verb
+			value:   &pb.Field{Value: &pb.Field_Str{}},
+			pattern: "replicas-%d",
+			args:    []*pb.Argument{strArg("three")},
+			err:     true,
+		},
+		{
+			value:   &pb.Field{Value: &pb.Field_Str{}},
+			pattern: "{{ lower .Value }}-{{ upper .Name }}",
+			args:    []*pb.Argument{strArg("WEB")},
+			out:     &pb.Field{Value: &pb.Field_Str{Str: "web-ENV"}},
+		},
+		{
+			value:   &pb.Field{Value: &pb.Field_Str{}},
+			pattern: `{{ join "," .Args }}`,
+			args:    []*pb.Argument{strArg("a"), numArg(1000000), boolArg(true)},
+			out:     &pb.Field{Value: &pb.Field_Str{Str: "a,1000000,true"}},
+		},
+		{
+			value:   &pb.Field{Value: &pb.Field_Str{}},
+			pattern: `{{ base64 .Value }}`,
+			args:    []*pb.Argument{strArg("secret")},
+			out:     &pb.Field{Value: &pb.Field_Str{Str: "c2VjcmV0"}},
+		},
+		{
+			value:   &pb.Field{Value: &pb.Field_Str{}},
+			pattern: `nginx:{{ default "latest" .Value }}`,
+			args:    []*pb.Argument{strArg("")},
+			out:     &pb.Field{Value: &pb.Field_Str{Str: "nginx:latest"}},
+		},
+		{ // typed number output
+			value:   &pb.Field{Value: &pb.Field_Number{}},
+			pattern: "{{ .Value }}0",
+			args:    []*pb.Argument{numArg(8)},
+			out:     &pb.Field{Value: &pb.Field_Number{Number: 80}},
+		},
+		{ // typed boolean output
+			value:   &pb.Field{Value: &pb.Field_Boolean{}},
+			pattern: `{{ if eq .Value "prod" }}true{{ else }}false{{ end }}`,
+			args:    []*pb.Argument{strArg("prod")},
+			out:     &pb.Field{Value: &pb.Field_Boolean{Boolean: true}},
+		},
+		{ // output isn't a number
+			value:   &pb.Field{Value: &pb.Field_Number{}},
+			pattern: "{{ .Value }}",
+			args:    []*pb.Argument{strArg("many")},
+			err:     true,
+		},
+		{ // unknown key
+			value:   &pb.Field{Value: &pb.Field_Str{}},
+			pattern: "{{ .Missing }}",
+			args:    []*pb.Argument{strArg("a")},
+			err:     true,
+		},
+	}
+
+	for i, test := range tests {
+		field := test.value
+		field.Param = &pb.Parameter{Name: "env", Pattern: test.pattern}
+
+		err := ApplyArguments(field, test.args...)
+		if test.err && err == nil {
+			t.Errorf("test %d: expected error, got value %v", i, field.GetValue())
+		} else if !test.err && err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		} else if !test.err && !FieldValueEquals(field, test.out) {
+			t.Errorf("test %d: expected %v, got %v", i, test.out.GetValue(), field.GetValue())
+		}
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	valid := []string{"", "nginx:%s", "{{ lower .Value }}", `{{ join "-" .Args }}`}
+	invalid := []string{"{{ .Value", "{{ unknown .Value }}"}
+
+	for _, pattern := range valid {
+		if err := ValidatePattern(pattern); err != nil {
+			t.Errorf("pattern '%s' should be valid: %v", pattern, err)
+		}
+	}
+
+	for _, pattern := range invalid {
+		if err := ValidatePattern(pattern); err == nil {
+			t.Errorf("pattern '%s' should be invalid", pattern)
+		}
+	}
+
+	if err := ValidateParameter(&pb.Parameter{Name: "bad", Pattern: "{{ .Value"}); err == nil {
+		t.Error("parameters with invalid patterns should be invalid")
+	}
+}
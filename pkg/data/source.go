@@ -0,0 +1,189 @@
+package data
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+const (
+	// SourceCluster computes an argument from a field of an object deployed to a cluster. The target is the path of the
+	// object and a fieldpath, such as "namespaces/default/replicationcontroller/web?spec.replicas".
+	SourceCluster = "cluster"
+
+	// SourceCommit computes an argument from the commit being deployed. The target is one of CommitTargets.
+	SourceCommit = "commit"
+
+	// SourceHash computes an argument by hashing the document or field addressed by an SRI. Relative SRIs refer to the
+	// documents being deployed.
+	SourceHash = "hash"
+
+	// SourceSecret computes an argument from the local secret store of a project. The target is the name of the secret.
+	SourceSecret = "secret"
+
+	// HashLen is the number of hex characters of hashes produced by SourceHash, short enough to be used as a label.
+	HashLen = 16
+)
+
+// CommitTargets are the targets of commit sources: the full and abbreviated OID of the commit, the most recent tag
+// reachable from it, and its message.
+var CommitTargets = []string{"id", "short-id", "tag", "message"}
+
+// A Source describes how the argument of a parameter is computed. It is stored in a parameter as "<kind>:<target>".
+type Source struct {
+	Kind   string
+	Target string
+}
+
+// ParseSource parses the source of a parameter, checking that the target is valid for its kind.
+func ParseSource(in string) (*Source, error) {
+	parts := strings.SplitN(in, ":", 2)
+	if len(parts) != 2 || len(parts[1]) == 0 {
+		return nil, fmt.Errorf("source '%s' must be in the form kind:target", in)
+	}
+
+	source := &Source{Kind: parts[0], Target: parts[1]}
+	switch source.Kind {
+	case SourceCluster:
+		sri, err := ParseSRI("*/" + source.Target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cluster source: %v", err)
+		} else if !sri.IsField() {
+			return nil, fmt.Errorf("cluster source '%s' must address a field", source.Target)
+		}
+	case SourceCommit:
+		if !containsString(CommitTargets, source.Target) {
+			return nil, fmt.Errorf("commit source must be one of: %s", strings.Join(CommitTargets, ", "))
+		}
+	case SourceHash:
+		sri, err := ParseSRI(source.Target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hash source: %v", err)
+		} else if sri.IsTree() {
+			return nil, fmt.Errorf("hash source '%s' must address a document or field", source.Target)
+		}
+	case SourceSecret:
+	default:
+		return nil, fmt.Errorf("unknown source kind '%s'", source.Kind)
+	}
+	return source, nil
+}
+
+// String returns the source in the form stored in parameters.
+func (s *Source) String() string {
+	return s.Kind + ":" + s.Target
+}
+
+// A SourceFunc computes an argument for target. Docs are the documents whose parameters are being satisfied.
+type SourceFunc func(target string, docs map[string]*pb.Document) (*pb.Argument, error)
+
+// Sources computes arguments for parameters with a source, keyed by kind of source.
+type Sources map[string]SourceFunc
+
+// Arguments computes arguments for the parameters in fields that have a source. Parameters named in skip are not
+// computed, allowing sources to be overridden.
+func (s Sources) Arguments(fields map[string][]*pb.Field, docs map[string]*pb.Document, skip Arguments) (Arguments, error) {
+	args := Arguments{}
+	for _, name := range ParameterNames(fields) {
+		in := fields[name][0].GetParam().Source
+		if _, skipped := skip[name]; skipped || len(in) == 0 {
+			continue
+		}
+
+		source, err := ParseSource(in)
+		if err != nil {
+			return nil, fmt.Errorf("parameter '%s': %v", name, err)
+		}
+
+		fn, ok := s[source.Kind]
+		if !ok {
+			return nil, fmt.Errorf("parameter '%s': %s sources can't be used here", name, source.Kind)
+		}
+
+		arg, err := fn(source.Target, docs)
+		if err != nil {
+			return nil, fmt.Errorf("could not compute '%s' from %s: %v", name, source, err)
+		}
+		args[name] = []*pb.Argument{arg}
+	}
+	return args, nil
+}
+
+// HashSource returns a SourceFunc for SourceHash. Relative SRIs are resolved against the documents being deployed,
+// others are retrieved using lookup. If lookup is nil, only relative SRIs can be used.
+func HashSource(lookup func(sri *SRI) (*pb.Document, error)) SourceFunc {
+	return func(target string, docs map[string]*pb.Document) (*pb.Argument, error) {
+		sri, err := ParseSRI(target)
+		if err != nil {
+			return nil, err
+		}
+
+		var doc *pb.Document
+		if sri.Treeish == "*" {
+			var ok bool
+			if doc, ok = docs[sri.Path]; !ok {
+				return nil, fmt.Errorf("no document with path '%s'", sri.Path)
+			}
+		} else if lookup == nil {
+			return nil, fmt.Errorf("'%s' must be relative", target)
+		} else if doc, err = lookup(sri); err != nil {
+			return nil, err
+		}
+
+		field := doc.GetRoot()
+		if sri.IsField() {
+			if field, err = GetFieldFromDocument(doc, sri.Field); err != nil {
+				return nil, err
+			}
+		}
+
+		hash, err := HashField(field)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.Argument{Value: &pb.Argument_Str{Str: hash}}, nil
+	}
+}
+
+// HashField returns the first HashLen hex characters of the SHA-256 hash of the JSON encoding of field's value. The
+// encoding doesn't depend on the order of object keys, so equal fields have equal hashes.
+func HashField(field *pb.Field) (string, error) {
+	val, err := decodeField(field)
+	if err != nil {
+		return "", fmt.Errorf("could not decode field: %v", err)
+	}
+
+	data, err := json.Marshal(val)
+	if err != nil {
+		return "", fmt.Errorf("could not encode field: %v", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:HashLen], nil
+}
+
+// ArgumentFromField returns an argument with the value of field, which must be a string, number, or boolean.
+func ArgumentFromField(field *pb.Field) (*pb.Argument, error) {
+	switch val := field.GetValue().(type) {
+	case *pb.Field_Number:
+		return &pb.Argument{Value: &pb.Argument_Number{Number: val.Number}}, nil
+	case *pb.Field_Str:
+		return &pb.Argument{Value: &pb.Argument_Str{Str: val.Str}}, nil
+	case *pb.Field_Boolean:
+		return &pb.Argument{Value: &pb.Argument_Boolean{Boolean: val.Boolean}}, nil
+	}
+	return nil, fmt.Errorf("field '%s' is not a string, number, or boolean", field.Key)
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
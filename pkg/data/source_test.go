@@ -0,0 +1,126 @@
+package data
+
+import (
+	"errors"
+	"testing"
+
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+func TestParseSource(t *testing.T) {
+	valid := []string{
+		"cluster:namespaces/default/replicationcontroller/web?spec.replicas",
+		"commit:short-id",
+		"hash:*/namespaces/default/configmap/settings",
+		"hash:*/namespaces/default/configmap/settings?data",
+	}
+	invalid := []string{
+		"",
+		"cluster",
+		"cluster:namespaces/default/replicationcontroller/web",
+		"commit:author",
+		"hash:*",
+		"git:HEAD",
+	}
+
+	for _, in := range valid {
+		source, err := ParseSource(in)
+		if err != nil {
+			t.Errorf("'%s' should be valid: %v", in, err)
+		} else if source.String() != in {
+			t.Errorf("expected '%s', got '%s'", in, source)
+		}
+	}
+
+	for _, in := range invalid {
+		if _, err := ParseSource(in); err == nil {
+			t.Errorf("'%s' should be invalid", in)
+		}
+	}
+
+	if err := ValidateParameter(&pb.Parameter{Name: "bad", Source: "commit:author"}); err == nil {
+		t.Error("parameters with invalid sources should be invalid")
+	}
+}
+
+func TestSourcesArguments(t *testing.T) {
+	docs := sharedDocs(t)
+	for _, field := range ParameterFields(docs)["version"] {
+		field.Param.Source = "commit:tag"
+	}
+
+	label, err := GetFieldFromDocument(docs["namespaces/default/replicationcontroller/web"], "labels")
+	if err != nil {
+		t.Fatal(err)
+	}
+	label.Param = &pb.Parameter{Name: "labels-hash", Source: "hash:*/namespaces/default/replicationcontroller/web?labels"}
+
+	sources := Sources{
+		SourceCommit: func(target string, _ map[string]*pb.Document) (*pb.Argument, error) {
+			return strArg("1.10"), nil
+		},
+		SourceHash: HashSource(nil),
+	}
+
+	fields := ParameterFields(docs)
+	args, err := sources.Arguments(fields, docs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(args["version"]) != 1 || args["version"][0].GetStr() != "1.10" {
+		t.Errorf("unexpected argument for version: %v", args["version"])
+	}
+
+	hash, err := HashField(label)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(hash) != HashLen {
+		t.Errorf("expected hash of length %d, got '%s'", HashLen, hash)
+	} else if len(args["labels-hash"]) != 1 || args["labels-hash"][0].GetStr() != hash {
+		t.Errorf("expected hash '%s', got %v", hash, args["labels-hash"])
+	}
+
+	// arguments that were given aren't computed
+	sources[SourceCommit] = func(string, map[string]*pb.Document) (*pb.Argument, error) {
+		return nil, errors.New("should not be called")
+	}
+	if _, err = sources.Arguments(fields, docs, Arguments{"version": {strArg("1.9")}}); err != nil {
+		t.Error(err)
+	}
+
+	delete(sources, SourceCommit)
+	if _, err = sources.Arguments(fields, docs, nil); err == nil {
+		t.Error("expected error for unavailable source")
+	}
+}
+
+func TestHashField(t *testing.T) {
+	a := &pb.Field{Value: &pb.Field_Object{Object: &pb.Object{Items: map[string]*pb.Field{
+		"a": {Key: "a", Value: &pb.Field_Str{Str: "1"}},
+		"b": {Key: "b", Value: &pb.Field_Number{Number: 2}},
+	}}}}
+	b := &pb.Field{Value: &pb.Field_Object{Object: &pb.Object{Items: map[string]*pb.Field{
+		"b": {Key: "b", Value: &pb.Field_Number{Number: 2}},
+		"a": {Key: "a", Value: &pb.Field_Str{Str: "1"}},
+	}}}}
+
+	hashA, err := HashField(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hashB, err := HashField(b)
+	if err != nil {
+		t.Fatal(err)
+	} else if hashA != hashB {
+		t.Errorf("equal fields should have equal hashes, got '%s' and '%s'", hashA, hashB)
+	}
+
+	b.GetObject().Items["a"].Value = &pb.Field_Str{Str: "changed"}
+	if hashB, err = HashField(b); err != nil {
+		t.Fatal(err)
+	} else if hashA == hashB {
+		t.Error("changing a field should change its hash")
+	}
+}
@@ -0,0 +1,261 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/distribution/reference"
+
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+const (
+	// minPort and maxPort are the bounds of PORT parameters.
+	minPort = 1
+	maxPort = 65535
+)
+
+// ParseParameterType returns the ParameterType named by name, ignoring case.
+func ParseParameterType(name string) (pb.ParameterType, error) {
+	paramType, ok := pb.ParameterType_value[strings.ToUpper(name)]
+	if !ok {
+		return pb.ParameterType_ANY, fmt.Errorf("unknown parameter type '%s'", name)
+	}
+	return pb.ParameterType(paramType), nil
+}
+
+// ValidateParameter checks that the type and constraints of param are consistent. The default value of param must
+// satisfy its constraints.
+func ValidateParameter(param *pb.Parameter) error {
+	if param == nil {
+		return ErrNilParameter
+	}
+
+	if err := ValidatePattern(param.Pattern); err != nil {
+		return fmt.Errorf("invalid pattern for '%s': %v", param.Name, err)
+	}
+
+	if _, err := regexp.Compile(param.Regex); err != nil {
+		return fmt.Errorf("invalid regex for '%s': %v", param.Name, err)
+	}
+
+	for _, bound := range []*pb.Argument{param.GetMin(), param.GetMax()} {
+		if bound != nil {
+			if _, isNum := bound.GetValue().(*pb.Argument_Number); !isNum {
+				return fmt.Errorf("bounds of '%s' must be numbers", param.Name)
+			}
+		}
+	}
+
+	if param.GetMin() != nil && param.GetMax() != nil && param.GetMin().GetNumber() > param.GetMax().GetNumber() {
+		return fmt.Errorf("min of '%s' is greater than its max", param.Name)
+	}
+
+	if param.Type == pb.ParameterType_ENUM && len(param.Values) == 0 {
+		return fmt.Errorf("enum parameter '%s' must have values", param.Name)
+	} else if param.Type != pb.ParameterType_ENUM && len(param.Values) != 0 {
+		return fmt.Errorf("only enum parameters can have values, '%s' is %s", param.Name, param.Type)
+	}
+
+	if len(param.Source) != 0 {
+		if _, err := ParseSource(param.Source); err != nil {
+			return fmt.Errorf("invalid source for '%s': %v", param.Name, err)
+		}
+	}
+
+	if IsSecretParameter(param) && param.GetDefault() != nil {
+		return fmt.Errorf("secret parameter '%s' cannot have a default, it would be committed", param.Name)
+	}
+
+	if param.GetDefault() != nil {
+		if _, err := CheckArgument(param, param.GetDefault()); err != nil {
+			return fmt.Errorf("invalid default: %v", err)
+		}
+	}
+	return nil
+}
+
+// IsSecretParameter returns true if the arguments of param are secret, either because it has the SECRET type or
+// because its arguments are read from the secret store.
+func IsSecretParameter(param *pb.Parameter) bool {
+	if param == nil {
+		return false
+	} else if param.Type == pb.ParameterType_SECRET {
+		return true
+	}
+
+	source, err := ParseSource(param.Source)
+	return err == nil && source.Kind == SourceSecret
+}
+
+// CheckArgument validates arg against the type and constraints of param. The argument is returned converted to the
+// type of param, which allows strings entered at a prompt to satisfy NUMBER, PORT, and BOOL parameters.
+func CheckArgument(param *pb.Parameter, arg *pb.Argument) (*pb.Argument, error) {
+	if param == nil {
+		return nil, ErrNilParameter
+	} else if arg.GetValue() == nil {
+		return nil, fmt.Errorf("no value given for '%s'", param.Name)
+	}
+
+	arg, err := convertArgument(param.Type, arg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid argument for '%s': %v", param.Name, err)
+	}
+
+	if err = checkType(param, arg); err != nil {
+		return nil, fmt.Errorf("invalid argument for '%s': %v", param.Name, err)
+	}
+
+	if err = checkConstraints(param, arg); err != nil {
+		return nil, fmt.Errorf("invalid argument for '%s': %v", param.Name, err)
+	}
+	return arg, nil
+}
+
+// DescribeParameter returns a short description of the type and constraints of param for display. An empty string is
+// returned for untyped parameters without constraints.
+func DescribeParameter(param *pb.Parameter) string {
+	var parts []string
+	if param.Type != pb.ParameterType_ANY {
+		parts = append(parts, strings.ToLower(param.Type.String()))
+	}
+
+	if len(param.Values) != 0 {
+		parts = append(parts, "one of "+strings.Join(param.Values, ", "))
+	}
+
+	if param.GetMin() != nil {
+		parts = append(parts, "min "+formatNumber(param.GetMin().GetNumber()))
+	}
+
+	if param.GetMax() != nil {
+		parts = append(parts, "max "+formatNumber(param.GetMax().GetNumber()))
+	}
+
+	if len(param.Regex) != 0 {
+		parts = append(parts, "matching "+param.Regex)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// convertArgument converts arg to the kind of value used by paramType.
+func convertArgument(paramType pb.ParameterType, arg *pb.Argument) (*pb.Argument, error) {
+	switch paramType {
+	case pb.ParameterType_NUMBER, pb.ParameterType_PORT:
+		if str, isStr := arg.GetValue().(*pb.Argument_Str); isStr {
+			num, err := strconv.ParseFloat(strings.TrimSpace(str.Str), 64)
+			if err != nil {
+				return nil, fmt.Errorf("'%s' is not a number", str.Str)
+			}
+			return &pb.Argument{Value: &pb.Argument_Number{Number: num}}, nil
+		}
+	case pb.ParameterType_BOOL:
+		if str, isStr := arg.GetValue().(*pb.Argument_Str); isStr {
+			b, err := strconv.ParseBool(strings.TrimSpace(str.Str))
+			if err != nil {
+				return nil, fmt.Errorf("'%s' is not true or false", str.Str)
+			}
+			return &pb.Argument{Value: &pb.Argument_Boolean{Boolean: b}}, nil
+		}
+	case pb.ParameterType_STRING, pb.ParameterType_ENUM, pb.ParameterType_DURATION, pb.ParameterType_IMAGE,
+		pb.ParameterType_SECRET:
+		if _, isStr := arg.GetValue().(*pb.Argument_Str); !isStr {
+			return &pb.Argument{Value: &pb.Argument_Str{Str: argString(arg)}}, nil
+		}
+	}
+	return arg, nil
+}
+
+// checkType checks that arg is a valid value of the type of param. The argument must already be converted.
+func checkType(param *pb.Parameter, arg *pb.Argument) error {
+	switch param.Type {
+	case pb.ParameterType_NUMBER:
+		if _, isNum := arg.GetValue().(*pb.Argument_Number); !isNum {
+			return fmt.Errorf("expected a number, got %s", argString(arg))
+		}
+	case pb.ParameterType_BOOL:
+		if _, isBool := arg.GetValue().(*pb.Argument_Boolean); !isBool {
+			return fmt.Errorf("expected true or false, got %s", argString(arg))
+		}
+	case pb.ParameterType_PORT:
+		num, isNum := arg.GetValue().(*pb.Argument_Number)
+		if !isNum || num.Number != math.Trunc(num.Number) || num.Number < minPort || num.Number > maxPort {
+			return fmt.Errorf("expected a port between %d and %d, got %s", minPort, maxPort, argString(arg))
+		}
+	case pb.ParameterType_ENUM:
+		for _, val := range param.Values {
+			if val == arg.GetStr() {
+				return nil
+			}
+		}
+		return fmt.Errorf("'%s' is not one of: %s", arg.GetStr(), strings.Join(param.Values, ", "))
+	case pb.ParameterType_DURATION:
+		if _, err := time.ParseDuration(arg.GetStr()); err != nil {
+			return fmt.Errorf("'%s' is not a duration such as 30s or 1h", arg.GetStr())
+		}
+	case pb.ParameterType_IMAGE:
+		if _, err := reference.ParseNamed(arg.GetStr()); err != nil {
+			return fmt.Errorf("'%s' is not an image reference: %v", arg.GetStr(), err)
+		}
+	}
+	return nil
+}
+
+// checkConstraints checks the regex and bounds of param against arg.
+func checkConstraints(param *pb.Parameter, arg *pb.Argument) error {
+	if len(param.Regex) != 0 {
+		re, err := regexp.Compile(param.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid regex: %v", err)
+		} else if !re.MatchString(argString(arg)) {
+			return fmt.Errorf("'%s' does not match %s", argString(arg), param.Regex)
+		}
+	}
+
+	var size float64
+	unit := ""
+	switch val := arg.GetValue().(type) {
+	case *pb.Argument_Number:
+		size = val.Number
+	case *pb.Argument_Str:
+		size, unit = float64(len(val.Str)), "length "
+	default:
+		return nil
+	}
+
+	if min := param.GetMin(); min != nil && size < min.GetNumber() {
+		return fmt.Errorf("%s%s is less than the minimum of %s", unit, formatNumber(size), formatNumber(min.GetNumber()))
+	}
+
+	if max := param.GetMax(); max != nil && size > max.GetNumber() {
+		return fmt.Errorf("%s%s is greater than the maximum of %s", unit, formatNumber(size), formatNumber(max.GetNumber()))
+	}
+	return nil
+}
+
+// argString returns the string form of arg.
+func argString(arg *pb.Argument) string {
+	switch val := arg.GetValue().(type) {
+	case *pb.Argument_Number:
+		return formatNumber(val.Number)
+	case *pb.Argument_Str:
+		return val.Str
+	case *pb.Argument_Boolean:
+		return strconv.FormatBool(val.Boolean)
+	}
+	return ""
+}
+
+func formatNumber(num float64) string {
+	return strconv.FormatFloat(num, 'f', -1, 64)
+}
+
+var (
+	// ErrNilParameter is returned when a parameter is required but nil was given.
+	ErrNilParameter = errors.New("parameter was nil")
+)
@@ -0,0 +1,142 @@
+package data
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+func numArg(num float64) *pb.Argument {
+	return &pb.Argument{Value: &pb.Argument_Number{Number: num}}
+}
+
+func strArg(str string) *pb.Argument {
+	return &pb.Argument{Value: &pb.Argument_Str{Str: str}}
+}
+
+func boolArg(b bool) *pb.Argument {
+	return &pb.Argument{Value: &pb.Argument_Boolean{Boolean: b}}
+}
+
+func TestCheckArgument(t *testing.T) {
+	tests := []struct {
+		param *pb.Parameter
+		arg   *pb.Argument
+		out   *pb.Argument
+		valid bool
+	}{
+		{&pb.Parameter{}, boolArg(true), boolArg(true), true},
+		{&pb.Parameter{Type: pb.ParameterType_STRING}, numArg(8), strArg("8"), true},
+		{&pb.Parameter{Type: pb.ParameterType_NUMBER}, strArg("2.5"), numArg(2.5), true},
+		{&pb.Parameter{Type: pb.ParameterType_NUMBER}, strArg("two"), nil, false},
+		{&pb.Parameter{Type: pb.ParameterType_NUMBER}, boolArg(true), nil, false},
+		{&pb.Parameter{Type: pb.ParameterType_BOOL}, strArg("true"), boolArg(true), true},
+		{&pb.Parameter{Type: pb.ParameterType_BOOL}, numArg(1), nil, false},
+		{&pb.Parameter{Type: pb.ParameterType_PORT}, numArg(8080), numArg(8080), true},
+		{&pb.Parameter{Type: pb.ParameterType_PORT}, strArg("443"), numArg(443), true},
+		{&pb.Parameter{Type: pb.ParameterType_PORT}, numArg(0), nil, false},
+		{&pb.Parameter{Type: pb.ParameterType_PORT}, numArg(70000), nil, false},
+		{&pb.Parameter{Type: pb.ParameterType_PORT}, numArg(80.5), nil, false},
+		{&pb.Parameter{Type: pb.ParameterType_ENUM, Values: []string{"dev", "prod"}}, strArg("prod"), strArg("prod"), true},
+		{&pb.Parameter{Type: pb.ParameterType_ENUM, Values: []string{"dev", "prod"}}, strArg("test"), nil, false},
+		{&pb.Parameter{Type: pb.ParameterType_DURATION}, strArg("1m30s"), strArg("1m30s"), true},
+		{&pb.Parameter{Type: pb.ParameterType_DURATION}, strArg("soon"), nil, false},
+		{&pb.Parameter{Type: pb.ParameterType_IMAGE}, strArg("redspread/spread:0.1"), strArg("redspread/spread:0.1"), true},
+		{&pb.Parameter{Type: pb.ParameterType_IMAGE}, strArg("Not An Image"), nil, false},
+		{&pb.Parameter{Regex: "^[a-z]+$"}, strArg("web"), strArg("web"), true},
+		{&pb.Parameter{Regex: "^[a-z]+$"}, strArg("Web"), nil, false},
+		{&pb.Parameter{Min: numArg(1), Max: numArg(5)}, numArg(3), numArg(3), true},
+		{&pb.Parameter{Min: numArg(1), Max: numArg(5)}, numArg(6), nil, false},
+		{&pb.Parameter{Type: pb.ParameterType_STRING, Max: numArg(3)}, strArg("long"), nil, false},
+		{&pb.Parameter{}, &pb.Argument{}, nil, false},
+	}
+
+	for i, test := range tests {
+		out, err := CheckArgument(test.param, test.arg)
+		if test.valid && err != nil {
+			t.Errorf("test %d: argument should be valid: %v", i, err)
+		} else if !test.valid && err == nil {
+			t.Errorf("test %d: argument should be invalid", i)
+		} else if test.valid && out.String() != test.out.String() {
+			t.Errorf("test %d: expected %v, got %v", i, test.out, out)
+		}
+	}
+}
+
+func TestValidateParameter(t *testing.T) {
+	valid := []*pb.Parameter{
+		{Name: "any"},
+		{Name: "env", Type: pb.ParameterType_ENUM, Values: []string{"dev", "prod"}, Default: strArg("dev")},
+		{Name: "replicas", Type: pb.ParameterType_NUMBER, Min: numArg(1), Max: numArg(10)},
+		{Name: "password", Type: pb.ParameterType_SECRET, Source: "secret:db-password"},
+	}
+
+	invalid := []*pb.Parameter{
+		nil,
+		{Name: "regex", Regex: "("},
+		{Name: "enum", Type: pb.ParameterType_ENUM},
+		{Name: "values", Type: pb.ParameterType_STRING, Values: []string{"a"}},
+		{Name: "bounds", Min: strArg("one")},
+		{Name: "order", Min: numArg(5), Max: numArg(1)},
+		{Name: "default", Type: pb.ParameterType_PORT, Default: numArg(0)},
+		{Name: "secret", Type: pb.ParameterType_SECRET, Default: strArg("hunter2")},
+		{Name: "stored", Source: "secret:db-password", Default: strArg("hunter2")},
+	}
+
+	for _, param := range valid {
+		if err := ValidateParameter(param); err != nil {
+			t.Errorf("parameter '%s' should be valid: %v", param.Name, err)
+		}
+	}
+
+	for _, param := range invalid {
+		if err := ValidateParameter(param); err == nil {
+			t.Errorf("parameter %v should be invalid", param)
+		}
+	}
+}
+
+func TestApplyArgumentsTyped(t *testing.T) {
+	field := &pb.Field{
+		Key:   "port",
+		Value: &pb.Field_Number{Number: 80},
+		Param: &pb.Parameter{Name: "port", Type: pb.ParameterType_PORT},
+	}
+
+	if err := ApplyArguments(field, strArg("8080")); err != nil {
+		t.Fatal(err)
+	} else if field.GetNumber() != 8080 {
+		t.Errorf("expected port to be converted to a number, got %v", field.GetValue())
+	}
+
+	if err := ApplyArguments(field, numArg(-1)); err == nil {
+		t.Error("invalid port should not be applied")
+	} else if field.GetNumber() != 8080 {
+		t.Error("field should not change when arguments are invalid")
+	}
+}
+
+func TestInteractiveArgsReprompt(t *testing.T) {
+	field := &pb.Field{
+		Key:   "replicas",
+		Value: &pb.Field_Number{Number: 1},
+		Param: &pb.Parameter{Name: "replicas", Type: pb.ParameterType_NUMBER, Max: numArg(5)},
+	}
+
+	in := ioutil.NopCloser(strings.NewReader("many\n9\n3\n"))
+	var out bytes.Buffer
+	if err := InteractiveArgs(in, &out, field, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if field.GetNumber() != 3 {
+		t.Errorf("expected first valid input to be applied, got %v", field.GetValue())
+	}
+
+	if count := strings.Count(out.String(), "Input:"); count != 3 {
+		t.Errorf("expected 3 prompts, got %d", count)
+	}
+}
@@ -0,0 +1,278 @@
+package deploy
+
+import (
+	"reflect"
+	"strings"
+
+	kube "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+)
+
+const (
+	// ServiceAccountTokenMountPath is where the token of a Pod's ServiceAccount is mounted by the cluster.
+	ServiceAccountTokenMountPath = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+	// defaultServiceAccount is the ServiceAccount used by Pods that don't specify one.
+	defaultServiceAccount = "default"
+
+	// defaultTerminationGracePeriod is the grace period set on Pods that don't specify one.
+	defaultTerminationGracePeriod = 30
+)
+
+// ManagedAnnotations are annotations set by the cluster or its controllers. They are removed when cleaning objects.
+var ManagedAnnotations = []string{
+	CreatedByAnnotation,
+	"deployment.kubernetes.io/",
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"pv.kubernetes.io/",
+	"volume.alpha.kubernetes.io/storage-provisioner",
+	"control-plane.alpha.kubernetes.io/leader",
+}
+
+// CleanFunc removes cluster specific information from obj. False is returned if the object was generated by the
+// cluster and should be excluded entirely.
+type CleanFunc func(obj KubeObject) (keep bool)
+
+// Cleaner removes information from objects that is managed by a cluster so they can be deployed to other clusters.
+// Cleaning is configured per kind.
+type Cleaner struct {
+	// Common are applied to objects of every kind.
+	Common []CleanFunc
+	// Kinds holds the functions applied to objects of a kind, keyed by lowercase kind (e.g. "service").
+	Kinds map[string][]CleanFunc
+}
+
+// NewCleaner returns a Cleaner that removes server managed metadata, status, defaulted fields, and objects generated
+// by the cluster.
+func NewCleaner() *Cleaner {
+	return &Cleaner{
+		Common: []CleanFunc{CleanMetadata, CleanStatus},
+		Kinds: map[string][]CleanFunc{
+			"namespace":             {cleanNamespace},
+			"persistentvolumeclaim": {cleanPersistentVolumeClaim},
+			"pod":                   {cleanPod},
+			"replicationcontroller": {cleanReplicationController},
+			"secret":                {cleanSecret},
+			"service":               {cleanService},
+			"serviceaccount":        {cleanServiceAccount},
+		},
+	}
+}
+
+// Register adds fns to the functions applied to objects of kind.
+func (c *Cleaner) Register(kind string, fns ...CleanFunc) {
+	if c.Kinds == nil {
+		c.Kinds = make(map[string][]CleanFunc)
+	}
+	kind = strings.ToLower(kind)
+	c.Kinds[kind] = append(c.Kinds[kind], fns...)
+}
+
+// Skip disables cleaning specific to kind. Common cleaning is still performed.
+func (c *Cleaner) Skip(kind string) {
+	delete(c.Kinds, strings.ToLower(kind))
+}
+
+// Clean modifies obj in place. False is returned if obj was generated by the cluster and shouldn't be kept.
+func (c *Cleaner) Clean(obj KubeObject) (keep bool, err error) {
+	gvk, err := objectKind(obj)
+	if err != nil {
+		return false, err
+	}
+
+	fns := append([]CleanFunc{}, c.Common...)
+	fns = append(fns, c.Kinds[strings.ToLower(gvk.Kind)]...)
+	for _, fn := range fns {
+		if !fn(obj) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// CleanMetadata removes fields of ObjectMeta set by the API server and annotations in ManagedAnnotations.
+func CleanMetadata(obj KubeObject) bool {
+	if objMeta, err := kube.ObjectMetaFor(obj); err == nil {
+		cleanObjectMeta(objMeta)
+	}
+	return true
+}
+
+// cleanObjectMeta removes fields set by the API server and annotations in ManagedAnnotations from objMeta.
+func cleanObjectMeta(objMeta *kube.ObjectMeta) {
+	objMeta.UID = ""
+	objMeta.ResourceVersion = ""
+	objMeta.SelfLink = ""
+	objMeta.Generation = 0
+	objMeta.CreationTimestamp = unversioned.Time{}
+	objMeta.DeletionTimestamp = nil
+	objMeta.DeletionGracePeriodSeconds = nil
+
+	for key := range objMeta.Annotations {
+		for _, managed := range ManagedAnnotations {
+			if key == managed || (strings.HasSuffix(managed, "/") && strings.HasPrefix(key, managed)) {
+				delete(objMeta.Annotations, key)
+			}
+		}
+	}
+
+	if len(objMeta.Annotations) == 0 {
+		objMeta.Annotations = nil
+	}
+}
+
+// CleanStatus clears the Status field of objects that have one.
+func CleanStatus(obj KubeObject) bool {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return true
+	}
+
+	status := v.Elem().FieldByName("Status")
+	if status.IsValid() && status.CanSet() {
+		status.Set(reflect.Zero(status.Type()))
+	}
+	return true
+}
+
+func cleanNamespace(obj KubeObject) bool {
+	ns := obj.(*kube.Namespace)
+
+	// finalizer is always added by the API server
+	var finalizers []kube.FinalizerName
+	for _, f := range ns.Spec.Finalizers {
+		if f != kube.FinalizerKubernetes {
+			finalizers = append(finalizers, f)
+		}
+	}
+	ns.Spec.Finalizers = finalizers
+	return true
+}
+
+func cleanPersistentVolumeClaim(obj KubeObject) bool {
+	pvc := obj.(*kube.PersistentVolumeClaim)
+
+	// volume is bound by the cluster
+	pvc.Spec.VolumeName = ""
+	return true
+}
+
+func cleanPod(obj KubeObject) bool {
+	pod := obj.(*kube.Pod)
+	cleanPodSpec(&pod.Spec)
+	return true
+}
+
+func cleanReplicationController(obj KubeObject) bool {
+	rc := obj.(*kube.ReplicationController)
+	if rc.Spec.Template != nil {
+		cleanObjectMeta(&rc.Spec.Template.ObjectMeta)
+		cleanPodSpec(&rc.Spec.Template.Spec)
+	}
+	return true
+}
+
+func cleanSecret(obj KubeObject) bool {
+	// tokens are generated for each ServiceAccount by the cluster
+	return obj.(*kube.Secret).Type != kube.SecretTypeServiceAccountToken
+}
+
+func cleanService(obj KubeObject) bool {
+	svc := obj.(*kube.Service)
+
+	// cluster IPs are allocated by the cluster, except for headless services
+	if svc.Spec.ClusterIP != kube.ClusterIPNone {
+		svc.Spec.ClusterIP = ""
+	}
+
+	// node ports are allocated by the cluster
+	for i := range svc.Spec.Ports {
+		svc.Spec.Ports[i].NodePort = 0
+	}
+
+	if svc.Spec.SessionAffinity == kube.ServiceAffinityNone {
+		svc.Spec.SessionAffinity = ""
+	}
+	return true
+}
+
+func cleanServiceAccount(obj KubeObject) bool {
+	sa := obj.(*kube.ServiceAccount)
+
+	// token secrets are generated by the cluster
+	var secrets []kube.ObjectReference
+	for _, secret := range sa.Secrets {
+		if !isTokenSecret(sa.Name, secret.Name) {
+			secrets = append(secrets, secret)
+		}
+	}
+	sa.Secrets = secrets
+
+	var pullSecrets []kube.LocalObjectReference
+	for _, secret := range sa.ImagePullSecrets {
+		if !strings.HasPrefix(secret.Name, sa.Name+"-dockercfg-") {
+			pullSecrets = append(pullSecrets, secret)
+		}
+	}
+	sa.ImagePullSecrets = pullSecrets
+	return true
+}
+
+// cleanPodSpec removes fields assigned by the scheduler, defaults, and the ServiceAccount token volume.
+func cleanPodSpec(spec *kube.PodSpec) {
+	spec.NodeName = ""
+
+	serviceAccount := spec.ServiceAccountName
+	if len(serviceAccount) == 0 {
+		serviceAccount = defaultServiceAccount
+	}
+
+	var volumes []kube.Volume
+	for _, vol := range spec.Volumes {
+		if vol.Secret == nil || !isTokenSecret(serviceAccount, vol.Secret.SecretName) {
+			volumes = append(volumes, vol)
+		}
+	}
+	spec.Volumes = volumes
+
+	for i := range spec.Containers {
+		ctr := &spec.Containers[i]
+
+		var mounts []kube.VolumeMount
+		for _, mount := range ctr.VolumeMounts {
+			if mount.MountPath != ServiceAccountTokenMountPath {
+				mounts = append(mounts, mount)
+			}
+		}
+		ctr.VolumeMounts = mounts
+
+		if ctr.TerminationMessagePath == kube.TerminationMessagePathDefault {
+			ctr.TerminationMessagePath = ""
+		}
+	}
+
+	if spec.ServiceAccountName == defaultServiceAccount {
+		spec.ServiceAccountName = ""
+	}
+
+	if spec.DNSPolicy == kube.DNSClusterFirst {
+		spec.DNSPolicy = ""
+	}
+
+	if spec.RestartPolicy == kube.RestartPolicyAlways {
+		spec.RestartPolicy = ""
+	}
+
+	if spec.TerminationGracePeriodSeconds != nil && *spec.TerminationGracePeriodSeconds == defaultTerminationGracePeriod {
+		spec.TerminationGracePeriodSeconds = nil
+	}
+
+	if spec.SecurityContext != nil && reflect.DeepEqual(*spec.SecurityContext, kube.PodSecurityContext{}) {
+		spec.SecurityContext = nil
+	}
+}
+
+// isTokenSecret returns true if secretName is the name of a token generated for serviceAccount.
+func isTokenSecret(serviceAccount, secretName string) bool {
+	return strings.HasPrefix(secretName, serviceAccount+"-token-")
+}
@@ -0,0 +1,214 @@
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kube "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+)
+
+func TestCleanMetadata(t *testing.T) {
+	now := unversioned.Now()
+	svc := &kube.Service{
+		ObjectMeta: kube.ObjectMeta{
+			Name:              "web",
+			UID:               "8f2c1a",
+			ResourceVersion:   "1024",
+			SelfLink:          "/api/v1/namespaces/default/services/web",
+			Generation:        2,
+			CreationTimestamp: now,
+			Annotations: map[string]string{
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				"deployment.kubernetes.io/revision":                "3",
+				"owner":                                            "team",
+			},
+		},
+		Status: kube.ServiceStatus{
+			LoadBalancer: kube.LoadBalancerStatus{
+				Ingress: []kube.LoadBalancerIngress{{IP: "10.0.0.1"}},
+			},
+		},
+	}
+
+	keep, err := NewCleaner().Clean(svc)
+	assert.NoError(t, err)
+	assert.True(t, keep)
+
+	assert.Equal(t, "web", svc.Name)
+	assert.Empty(t, svc.UID)
+	assert.Empty(t, svc.ResourceVersion)
+	assert.Empty(t, svc.SelfLink)
+	assert.Zero(t, svc.Generation)
+	assert.True(t, svc.CreationTimestamp.IsZero())
+	assert.Equal(t, map[string]string{"owner": "team"}, svc.Annotations)
+	assert.Equal(t, kube.ServiceStatus{}, svc.Status)
+}
+
+func TestCleanService(t *testing.T) {
+	svc := &kube.Service{
+		ObjectMeta: kube.ObjectMeta{Name: "web"},
+		Spec: kube.ServiceSpec{
+			Type:            kube.ServiceTypeNodePort,
+			ClusterIP:       "10.0.0.12",
+			SessionAffinity: kube.ServiceAffinityNone,
+			Ports:           []kube.ServicePort{{Port: 80, NodePort: 31380}},
+		},
+	}
+
+	keep, err := NewCleaner().Clean(svc)
+	assert.NoError(t, err)
+	assert.True(t, keep)
+
+	assert.Empty(t, svc.Spec.ClusterIP)
+	assert.Empty(t, svc.Spec.SessionAffinity)
+	assert.Zero(t, svc.Spec.Ports[0].NodePort)
+	assert.Equal(t, kube.ServiceTypeNodePort, svc.Spec.Type)
+
+	headless := &kube.Service{
+		ObjectMeta: kube.ObjectMeta{Name: "db"},
+		Spec: kube.ServiceSpec{
+			ClusterIP: kube.ClusterIPNone,
+			Ports:     []kube.ServicePort{{Port: 5432}},
+		},
+	}
+
+	keep, err = NewCleaner().Clean(headless)
+	assert.NoError(t, err)
+	assert.True(t, keep)
+	assert.Equal(t, kube.ClusterIPNone, headless.Spec.ClusterIP, "headless services should stay headless")
+}
+
+func TestCleanTokenSecret(t *testing.T) {
+	token := &kube.Secret{
+		ObjectMeta: kube.ObjectMeta{Name: "default-token-x8k2p"},
+		Type:       kube.SecretTypeServiceAccountToken,
+	}
+	opaque := &kube.Secret{
+		ObjectMeta: kube.ObjectMeta{Name: "database"},
+		Type:       kube.SecretTypeOpaque,
+	}
+
+	cleaner := NewCleaner()
+
+	keep, err := cleaner.Clean(token)
+	assert.NoError(t, err)
+	assert.False(t, keep, "service account tokens should be excluded")
+
+	keep, err = cleaner.Clean(opaque)
+	assert.NoError(t, err)
+	assert.True(t, keep)
+}
+
+func TestCleanServiceAccount(t *testing.T) {
+	sa := &kube.ServiceAccount{
+		ObjectMeta: kube.ObjectMeta{Name: "builder"},
+		Secrets: []kube.ObjectReference{
+			{Name: "builder-token-4hd9s"},
+			{Name: "registry"},
+		},
+		ImagePullSecrets: []kube.LocalObjectReference{
+			{Name: "builder-dockercfg-7fm2q"},
+		},
+	}
+
+	keep, err := NewCleaner().Clean(sa)
+	assert.NoError(t, err)
+	assert.True(t, keep)
+
+	assert.Equal(t, []kube.ObjectReference{{Name: "registry"}}, sa.Secrets)
+	assert.Nil(t, sa.ImagePullSecrets)
+}
+
+func TestCleanReplicationController(t *testing.T) {
+	grace := int64(defaultTerminationGracePeriod)
+	rc := &kube.ReplicationController{
+		ObjectMeta: kube.ObjectMeta{Name: "web"},
+		Spec: kube.ReplicationControllerSpec{
+			Template: &kube.PodTemplateSpec{
+				ObjectMeta: kube.ObjectMeta{
+					Labels:            map[string]string{"app": "web"},
+					CreationTimestamp: unversioned.Now(),
+				},
+				Spec: kube.PodSpec{
+					NodeName:                      "node-1",
+					RestartPolicy:                 kube.RestartPolicyAlways,
+					DNSPolicy:                     kube.DNSClusterFirst,
+					TerminationGracePeriodSeconds: &grace,
+					SecurityContext:               &kube.PodSecurityContext{},
+					Volumes: []kube.Volume{
+						{
+							Name: "default-token-x8k2p",
+							VolumeSource: kube.VolumeSource{
+								Secret: &kube.SecretVolumeSource{SecretName: "default-token-x8k2p"},
+							},
+						},
+						{
+							Name: "data",
+							VolumeSource: kube.VolumeSource{
+								EmptyDir: &kube.EmptyDirVolumeSource{},
+							},
+						},
+					},
+					Containers: []kube.Container{
+						{
+							Name:                   "web",
+							Image:                  "nginx",
+							TerminationMessagePath: kube.TerminationMessagePathDefault,
+							VolumeMounts: []kube.VolumeMount{
+								{Name: "default-token-x8k2p", MountPath: ServiceAccountTokenMountPath},
+								{Name: "data", MountPath: "/data"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	keep, err := NewCleaner().Clean(rc)
+	assert.NoError(t, err)
+	assert.True(t, keep)
+
+	tmpl := rc.Spec.Template
+	assert.True(t, tmpl.CreationTimestamp.IsZero())
+	assert.Equal(t, map[string]string{"app": "web"}, tmpl.Labels)
+
+	spec := tmpl.Spec
+	assert.Empty(t, spec.NodeName)
+	assert.Empty(t, spec.RestartPolicy)
+	assert.Empty(t, spec.DNSPolicy)
+	assert.Nil(t, spec.TerminationGracePeriodSeconds)
+	assert.Nil(t, spec.SecurityContext)
+
+	if assert.Len(t, spec.Volumes, 1) {
+		assert.Equal(t, "data", spec.Volumes[0].Name)
+	}
+
+	ctr := spec.Containers[0]
+	assert.Empty(t, ctr.TerminationMessagePath)
+	assert.Equal(t, []kube.VolumeMount{{Name: "data", MountPath: "/data"}}, ctr.VolumeMounts)
+}
+
+func TestCleanerRegisterSkip(t *testing.T) {
+	cleaner := NewCleaner()
+	cleaner.Skip("Service")
+	cleaner.Register("Service", func(obj KubeObject) bool {
+		return obj.(*kube.Service).Name != "excluded"
+	})
+
+	svc := &kube.Service{
+		ObjectMeta: kube.ObjectMeta{Name: "web", UID: "8f2c1a"},
+		Spec:       kube.ServiceSpec{ClusterIP: "10.0.0.12"},
+	}
+
+	keep, err := cleaner.Clean(svc)
+	assert.NoError(t, err)
+	assert.True(t, keep)
+	assert.Equal(t, "10.0.0.12", svc.Spec.ClusterIP, "skipped cleaning should not be applied")
+	assert.Empty(t, svc.UID, "common cleaning should still be applied")
+
+	keep, err = cleaner.Clean(&kube.Service{ObjectMeta: kube.ObjectMeta{Name: "excluded"}})
+	assert.NoError(t, err)
+	assert.False(t, keep)
+}
@@ -204,6 +204,62 @@ func (c *KubeCluster) Get(kind, namespace, name string, export bool) (KubeObject
 	return kubeObj, nil
 }
 
+// GetPath retrieves the object at path from the cluster. Path uses the format returned by ObjectPath.
+func (c *KubeCluster) GetPath(path string, export bool) (KubeObject, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) != 4 || parts[0] != "namespaces" {
+		return nil, fmt.Errorf("'%s' is not an object path", path)
+	}
+
+	base := BaseObject(parts[2])
+	if base == nil {
+		return nil, fmt.Errorf("unable to find Kind for '%s'", parts[2])
+	}
+
+	mapping, err := mapping(base)
+	if err != nil {
+		return nil, err
+	}
+	return c.get(parts[1], parts[3], export, mapping)
+}
+
+// List retrieves every object of kind in namespace matching selector. If export is true, each object is retrieved
+// individually with cluster-specific information removed by the API server.
+func (c *KubeCluster) List(kind, namespace string, selector labels.Selector, export bool) ([]KubeObject, error) {
+	kind = KubeShortForm(kind)
+
+	req := c.Client.Get().Resource(kind).Namespace(namespace)
+	if selector != nil {
+		req.LabelsSelectorParam(selector)
+	}
+
+	runObj, err := req.Do().Get()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list '%s' (namespace=%s) from Kube API server: %v", kind, namespace, err)
+	}
+
+	items, err := meta.ExtractList(runObj)
+	if err != nil {
+		return nil, fmt.Errorf("could not read list of '%s': %v", kind, err)
+	}
+
+	objects := make([]KubeObject, 0, len(items))
+	for _, item := range items {
+		obj, err := AsKubeObject(item)
+		if err != nil {
+			return nil, err
+		}
+
+		if export {
+			if obj, err = c.Get(kind, namespace, obj.GetObjectMeta().GetName(), true); err != nil {
+				return nil, err
+			}
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
 // get retrieves the object from the cluster.
 func (c *KubeCluster) get(namespace, name string, export bool, mapping *meta.RESTMapping) (KubeObject, error) {
 	req := c.Client.RESTClient.Get().Name(name)
@@ -0,0 +1,108 @@
+package deploy
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	kube "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/apimachinery/registered"
+	"k8s.io/kubernetes/pkg/runtime"
+)
+
+const (
+	// FormatYAML encodes manifests as YAML.
+	FormatYAML = "yaml"
+	// FormatJSON encodes manifests as indented JSON.
+	FormatJSON = "json"
+)
+
+// EncodeObject encodes obj as a manifest in format using the preferred version of the object's API group.
+func EncodeObject(obj KubeObject, format string) ([]byte, error) {
+	gvk, err := objectKind(obj)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine kind: %v", err)
+	}
+
+	group, err := registered.Group(gvk.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	var serializer runtime.Serializer
+	switch format {
+	case FormatYAML:
+		serializer, _ = kube.Codecs.SerializerForMediaType("application/yaml", nil)
+	case FormatJSON:
+		serializer, _ = kube.Codecs.SerializerForMediaType("application/json", map[string]string{"pretty": "1"})
+	default:
+		return nil, fmt.Errorf("unknown format '%s'", format)
+	}
+
+	encoder := kube.Codecs.EncoderForVersion(serializer, group.GroupVersion)
+	return runtime.Encode(encoder, obj)
+}
+
+// WriteManifests writes each object of dep to a file in dir. The layout of dir mirrors ObjectPath with the format as
+// the file extension. The paths of the written files are returned in order.
+func WriteManifests(dep *Deployment, dir, format string) ([]string, error) {
+	var written []string
+	for _, path := range dep.paths() {
+		data, err := EncodeObject(dep.objects[path], format)
+		if err != nil {
+			return written, fmt.Errorf("could not encode '%s': %v", path, err)
+		}
+
+		filename := filepath.Join(dir, filepath.FromSlash(path)) + "." + format
+		if err = os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return written, err
+		}
+
+		if err = ioutil.WriteFile(filename, data, 0644); err != nil {
+			return written, err
+		}
+		written = append(written, filename)
+	}
+	return written, nil
+}
+
+// WriteManifestStream writes the objects of dep to w as a single stream. YAML documents are separated by "---",
+// JSON objects are concatenated. Both can be read by kubectl.
+func WriteManifestStream(w io.Writer, dep *Deployment, format string) error {
+	for i, path := range dep.paths() {
+		data, err := EncodeObject(dep.objects[path], format)
+		if err != nil {
+			return fmt.Errorf("could not encode '%s': %v", path, err)
+		}
+
+		if i > 0 && format == FormatYAML {
+			if _, err = io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+
+		if !bytes.HasSuffix(data, []byte("\n")) {
+			data = append(data, '\n')
+		}
+
+		if _, err = w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// paths returns the paths of the objects in the Deployment in sorted order. Cluster scoped objects, such as
+// Namespaces, sort before namespaced objects.
+func (d Deployment) paths() []string {
+	paths := make([]string, 0, len(d.objects))
+	for path := range d.objects {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
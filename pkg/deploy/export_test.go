@@ -0,0 +1,88 @@
+package deploy
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kube "k8s.io/kubernetes/pkg/api"
+)
+
+func testExportDeployment(t *testing.T) *Deployment {
+	dep := new(Deployment)
+	objects := []KubeObject{
+		&kube.Service{
+			ObjectMeta: kube.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: kube.ServiceSpec{
+				Ports: []kube.ServicePort{{Port: 80}},
+			},
+		},
+		&kube.Namespace{
+			ObjectMeta: kube.ObjectMeta{Name: "staging"},
+		},
+	}
+
+	for _, obj := range objects {
+		if err := dep.Add(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dep
+}
+
+func TestEncodeObjectJSON(t *testing.T) {
+	svc := &kube.Service{ObjectMeta: kube.ObjectMeta{Name: "web", Namespace: "default"}}
+
+	out, err := EncodeObject(svc, FormatJSON)
+	assert.NoError(t, err)
+
+	var manifest map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out, &manifest))
+	assert.Equal(t, "v1", manifest["apiVersion"])
+	assert.Equal(t, "Service", manifest["kind"])
+}
+
+func TestEncodeObjectUnknownFormat(t *testing.T) {
+	_, err := EncodeObject(&kube.Service{}, "toml")
+	assert.Error(t, err)
+}
+
+func TestWriteManifestStream(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteManifestStream(&buf, testExportDeployment(t), FormatYAML)
+	assert.NoError(t, err)
+
+	docs := strings.Split(buf.String(), "---\n")
+	if assert.Len(t, docs, 2) {
+		assert.Contains(t, docs[0], "kind: Namespace", "namespaces should be written first")
+		assert.Contains(t, docs[1], "kind: Service")
+		assert.Contains(t, docs[1], "apiVersion: v1")
+	}
+}
+
+func TestWriteManifests(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spread-export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	written, err := WriteManifests(testExportDeployment(t), dir, FormatJSON)
+	assert.NoError(t, err)
+
+	expected := []string{
+		filepath.Join(dir, "namespaces", "", "namespace", "staging.json"),
+		filepath.Join(dir, "namespaces", "default", "service", "web.json"),
+	}
+	assert.Equal(t, expected, written)
+
+	for _, path := range expected {
+		_, err = os.Stat(path)
+		assert.NoError(t, err)
+	}
+}
@@ -65,6 +65,20 @@ var resources = []string{
 	"services",
 }
 
+// NamespacedResources are the resources retrieved when snapshotting an entire namespace. Resources that are always
+// generated by the cluster, such as endpoints, are excluded.
+var NamespacedResources = []string{
+	"configmaps",
+	"limitranges",
+	"persistentvolumeclaims",
+	"pods",
+	"replicationcontrollers",
+	"resourcequotas",
+	"secrets",
+	"serviceaccounts",
+	"services",
+}
+
 // BaseObject returns a Kubernetes object of the given kind to be used to populate.
 // Nil is returned if the Kind is unknown
 func BaseObject(kind string) KubeObject {
@@ -46,6 +46,16 @@ func objectKind(obj KubeObject) (types.GroupVersionKind, error) {
 	return gkv, nil
 }
 
+// CreatedByAnnotation is set by controllers on the objects they create.
+const CreatedByAnnotation = "kubernetes.io/created-by"
+
+// ControllerManaged returns true if obj was created by a controller, such as the pods of a ReplicationController.
+// These objects are recreated by their controller and shouldn't be versioned separately.
+func ControllerManaged(obj KubeObject) bool {
+	_, ok := obj.GetObjectMeta().GetAnnotations()[CreatedByAnnotation]
+	return ok
+}
+
 func KubeObjectFromDocument(path string, doc *pb.Document) (KubeObject, error) {
 	kind, err := kindFromPath(path)
 	if err != nil {
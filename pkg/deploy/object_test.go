@@ -40,3 +40,18 @@ func TestObjectPathNoNamespace(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expected, actual)
 }
+
+func TestControllerManaged(t *testing.T) {
+	pod := &kube.Pod{
+		ObjectMeta: kube.ObjectMeta{
+			Name: "johnson-x7f2k",
+			Annotations: map[string]string{
+				CreatedByAnnotation: `{"kind":"SerializedReference"}`,
+			},
+		},
+	}
+	assert.True(t, ControllerManaged(pod), "pod created by RC should be controller managed")
+
+	pod.Annotations = nil
+	assert.False(t, ControllerManaged(pod), "pod without annotation should not be controller managed")
+}
@@ -0,0 +1,181 @@
+// Package hostkeys verifies the identity of Git remotes using SSH known_hosts files and the system's TLS roots.
+package hostkeys
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+
+	"rsprd.com/spread/pkg/config"
+)
+
+// DefaultKnownHosts are the known_hosts files used when none are configured.
+var DefaultKnownHosts = []string{"~/.ssh/known_hosts", "/etc/ssh/ssh_known_hosts"}
+
+// DefaultSSHPort is the port used for SSH remotes that don't specify one.
+const DefaultSSHPort = 22
+
+// HostKey identifies the key presented by an SSH server. Either hash may be nil if it wasn't provided.
+type HostKey struct {
+	MD5  []byte
+	SHA1 []byte
+}
+
+// Checker verifies SSH host keys and TLS certificates of remotes.
+type Checker struct {
+	// KnownHosts are paths to files in OpenSSH known_hosts format. Files that don't exist are ignored.
+	KnownHosts []string
+	// Insecure are the names or URLs of remotes that are not verified.
+	Insecure []string
+}
+
+// FromConfig returns a Checker using the known_hosts files and remote settings in cfg.
+func FromConfig(cfg *config.Config) *Checker {
+	c := &Checker{
+		KnownHosts: cfg.KnownHosts,
+	}
+	if len(c.KnownHosts) == 0 {
+		c.KnownHosts = DefaultKnownHosts
+	}
+
+	for remote, remoteCfg := range cfg.Remotes {
+		if remoteCfg.Insecure {
+			c.Insecure = append(c.Insecure, remote)
+		}
+	}
+	return c
+}
+
+// Default returns the Checker used when no configuration is given.
+func Default() *Checker {
+	return FromConfig(&config.Config{})
+}
+
+// IsInsecure returns true if verification has been disabled for the remote with name or rawurl.
+func (c *Checker) IsInsecure(name, rawurl string) bool {
+	for _, remote := range c.Insecure {
+		if (len(name) != 0 && remote == name) || remote == rawurl {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyHostKey checks that key is listed for host in a known_hosts file. ErrUnknownHost is returned if the host is
+// not listed and ErrHostKeyMismatch if it is listed with a different key.
+func (c *Checker) VerifyHostKey(host string, port int, key HostKey) error {
+	if len(key.MD5) == 0 && len(key.SHA1) == 0 {
+		return errors.New("no host key hash was provided")
+	}
+
+	known := false
+	for _, path := range c.KnownHosts {
+		expanded, err := homedir.Expand(path)
+		if err != nil {
+			return err
+		}
+
+		entries, err := ReadKnownHosts(expanded)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if !entry.MatchHost(host, port) {
+				continue
+			}
+
+			matches := entry.MatchKey(key)
+			switch {
+			case entry.Marker == MarkerRevoked && matches:
+				return fmt.Errorf("%v: host key for '%s' has been revoked in %s", ErrHostKeyMismatch, host, path)
+			case entry.Marker == MarkerRevoked, entry.Marker == MarkerCertAuthority:
+				// other revoked keys don't make the host known, and certificates aren't exposed by libgit2
+				continue
+			case matches:
+				return nil
+			}
+			known = true
+		}
+	}
+
+	if known {
+		return fmt.Errorf("%v: key presented by '%s' (%s) does not match known_hosts, this may be a man-in-the-middle attack",
+			ErrHostKeyMismatch, host, key.Fingerprint())
+	}
+	return fmt.Errorf("%v: '%s' (%s) is not in known_hosts, add it using ssh-keyscan or by connecting with ssh",
+		ErrUnknownHost, host, key.Fingerprint())
+}
+
+// VerifyCertificate checks that cert is valid for host using the system's root certificates. Valid indicates that
+// the certificate chain was already verified by the transport.
+func (c *Checker) VerifyCertificate(host string, cert *x509.Certificate, valid bool) error {
+	if valid {
+		return nil
+	} else if cert == nil {
+		return fmt.Errorf("%v: no certificate was presented by '%s'", ErrInvalidCertificate, host)
+	}
+
+	if _, err := cert.Verify(x509.VerifyOptions{DNSName: host}); err != nil {
+		return fmt.Errorf("%v: %v", ErrInvalidCertificate, err)
+	}
+	return nil
+}
+
+// Fingerprint returns the hash of the key in the format used by OpenSSH.
+func (k HostKey) Fingerprint() string {
+	if len(k.MD5) != 0 {
+		return "MD5:" + colonHex(k.MD5)
+	}
+	return "SHA1:" + colonHex(k.SHA1)
+}
+
+// Port returns the port of the SSH remote at rawurl. DefaultSSHPort is returned if none is given.
+func Port(rawurl string) int {
+	if !strings.Contains(rawurl, "://") {
+		// scp-like syntax (user@host:path) can't specify a port
+		return DefaultSSHPort
+	}
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return DefaultSSHPort
+	}
+
+	_, portStr, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		return DefaultSSHPort
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return DefaultSSHPort
+	}
+	return port
+}
+
+// colonHex formats data as colon separated hex bytes.
+func colonHex(data []byte) string {
+	parts := make([]string, len(data))
+	for i, b := range data {
+		parts[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+var (
+	// ErrUnknownHost is returned when a host is not listed in any known_hosts file.
+	ErrUnknownHost = errors.New("unknown host")
+
+	// ErrHostKeyMismatch is returned when a host presents a key different from the one that is trusted.
+	ErrHostKeyMismatch = errors.New("host key verification failed")
+
+	// ErrInvalidCertificate is returned when a TLS certificate can't be verified.
+	ErrInvalidCertificate = errors.New("certificate verification failed")
+)
@@ -0,0 +1,196 @@
+package hostkeys
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"rsprd.com/spread/pkg/config"
+)
+
+const (
+	testKey      = "AAAAC3NzaC1lZDI1NTE5AAAAIFz1KtN8VFgq71C3A0P8fHo3zC707YS2GTo8XocFsHMj"
+	testOtherKey = "AAAAC3NzaC1lZDI1NTE5AAAAIBY6Yob0S8gc3OQ6Cly6L1eigGOdpM02IjhXwJ0P/Wd8"
+)
+
+func hostKey(t *testing.T, encoded string, useMD5 bool) HostKey {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if useMD5 {
+		sum := md5.Sum(key)
+		return HostKey{MD5: sum[:]}
+	}
+	sum := sha1.Sum(key)
+	return HostKey{SHA1: sum[:]}
+}
+
+func hashHost(name string) string {
+	salt := []byte("0123456789abcdefghij")
+	mac := hmac.New(sha1.New, salt)
+	mac.Write([]byte(name))
+	return hashedPrefix + base64.StdEncoding.EncodeToString(salt) + "|" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func knownHostsFile(t *testing.T, lines ...string) string {
+	f, err := ioutil.TempFile("", "spread-known-hosts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err = f.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		t.Fatal(err)
+	}
+	return f.Name()
+}
+
+func TestMatchHost(t *testing.T) {
+	tests := []struct {
+		hosts   string
+		host    string
+		port    int
+		matches bool
+	}{
+		{"github.com", "github.com", 22, true},
+		{"github.com", "GitHub.com", 22, true},
+		{"github.com", "github.com", 2222, false},
+		{"[github.com]:2222", "github.com", 2222, true},
+		{"gitlab.com,github.com", "github.com", 22, true},
+		{"*.example.com", "git.example.com", 22, true},
+		{"*.example.com,!secret.example.com", "secret.example.com", 22, false},
+		{"git?.example.com", "git1.example.com", 22, true},
+		{hashHost("github.com"), "github.com", 22, true},
+		{hashHost("github.com"), "gitlab.com", 22, false},
+		{hashHost("[github.com]:2222"), "github.com", 2222, true},
+	}
+
+	for i, test := range tests {
+		entry, ok := ParseEntry(fmt.Sprintf("%s ssh-ed25519 %s", test.hosts, testKey))
+		if !ok {
+			t.Fatalf("test %d: could not parse entry", i)
+		}
+
+		if matches := entry.MatchHost(test.host, test.port); matches != test.matches {
+			t.Errorf("test %d: expected match to be %v for %s:%d against '%s'", i, test.matches, test.host, test.port, test.hosts)
+		}
+	}
+}
+
+func TestParseEntry(t *testing.T) {
+	invalid := []string{
+		"",
+		"# github.com ssh-ed25519 " + testKey,
+		"github.com ssh-ed25519",
+		"github.com ssh-ed25519 not-base64!",
+	}
+
+	for _, line := range invalid {
+		if _, ok := ParseEntry(line); ok {
+			t.Errorf("line should not have parsed: '%s'", line)
+		}
+	}
+
+	entry, ok := ParseEntry("@revoked github.com ssh-ed25519 " + testKey + " comment")
+	if !ok {
+		t.Fatal("could not parse entry with marker")
+	} else if entry.Marker != MarkerRevoked || entry.KeyType != "ssh-ed25519" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+}
+
+func TestVerifyHostKey(t *testing.T) {
+	path := knownHostsFile(t,
+		"# known hosts",
+		"github.com ssh-ed25519 "+testKey,
+		"[git.example.com]:2222 ssh-ed25519 "+testOtherKey,
+		"@revoked revoked.example.com ssh-ed25519 "+testKey,
+		"revoked.example.com ssh-ed25519 "+testKey,
+		"@revoked unknown.example.com ssh-ed25519 "+testOtherKey,
+	)
+	defer os.Remove(path)
+
+	checker := &Checker{KnownHosts: []string{"/does/not/exist", path}}
+
+	tests := []struct {
+		host string
+		port int
+		key  string
+		md5  bool
+		err  error
+	}{
+		{"github.com", 22, testKey, false, nil},
+		{"github.com", 22, testKey, true, nil},
+		{"github.com", 22, testOtherKey, false, ErrHostKeyMismatch},
+		{"git.example.com", 2222, testOtherKey, false, nil},
+		{"git.example.com", 22, testOtherKey, false, ErrUnknownHost},
+		{"gitlab.com", 22, testKey, false, ErrUnknownHost},
+		{"revoked.example.com", 22, testKey, false, ErrHostKeyMismatch},
+		{"unknown.example.com", 22, testKey, false, ErrUnknownHost},
+	}
+
+	for i, test := range tests {
+		err := checker.VerifyHostKey(test.host, test.port, hostKey(t, test.key, test.md5))
+		if test.err == nil && err != nil {
+			t.Errorf("test %d: host key should be trusted: %v", i, err)
+		} else if test.err != nil && (err == nil || !strings.HasPrefix(err.Error(), test.err.Error())) {
+			t.Errorf("test %d: expected error '%v', got '%v'", i, test.err, err)
+		}
+	}
+}
+
+func TestInsecure(t *testing.T) {
+	checker := FromConfig(&config.Config{
+		Remotes: map[string]config.RemoteConfig{
+			"origin":                      {Insecure: true},
+			"git@git.example.com:app.git": {Insecure: true},
+			"upstream":                    {},
+		},
+	})
+
+	if len(checker.KnownHosts) != len(DefaultKnownHosts) {
+		t.Error("default known hosts should be used")
+	}
+
+	tests := []struct {
+		name, url string
+		insecure  bool
+	}{
+		{"origin", "git@github.com:app.git", true},
+		{"", "git@git.example.com:app.git", true},
+		{"upstream", "git@github.com:app.git", false},
+		{"", "origin", true},
+		{"", "git@github.com:app.git", false},
+	}
+
+	for i, test := range tests {
+		if insecure := checker.IsInsecure(test.name, test.url); insecure != test.insecure {
+			t.Errorf("test %d: expected insecure to be %v", i, test.insecure)
+		}
+	}
+}
+
+func TestPort(t *testing.T) {
+	tests := []struct {
+		url  string
+		port int
+	}{
+		{"git@github.com:redspread/spread.git", 22},
+		{"ssh://git@github.com/redspread/spread.git", 22},
+		{"ssh://git@git.example.com:2222/app.git", 2222},
+	}
+
+	for _, test := range tests {
+		if port := Port(test.url); port != test.port {
+			t.Errorf("expected port %d for '%s', got %d", test.port, test.url, port)
+		}
+	}
+}
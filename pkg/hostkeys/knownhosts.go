@@ -0,0 +1,181 @@
+package hostkeys
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MarkerCertAuthority marks a key as a certificate authority for the matching hosts.
+	MarkerCertAuthority = "@cert-authority"
+	// MarkerRevoked marks a key that must not be accepted for the matching hosts.
+	MarkerRevoked = "@revoked"
+
+	// hashedPrefix starts a host entry hashed by 'ssh-keygen -H'.
+	hashedPrefix = "|1|"
+)
+
+// Entry is a single line of a known_hosts file.
+type Entry struct {
+	// Marker is either empty, MarkerCertAuthority, or MarkerRevoked.
+	Marker string
+	// Hosts are the host patterns of the entry. A pattern may be hashed.
+	Hosts []string
+	// KeyType is the algorithm of the key, such as "ssh-ed25519".
+	KeyType string
+	// Key is the decoded public key.
+	Key []byte
+}
+
+// ReadKnownHosts parses the known_hosts file at path. No entries are returned if the file doesn't exist. Lines that
+// can't be parsed are skipped, like OpenSSH does.
+func ReadKnownHosts(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read known hosts: %v", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if entry, ok := ParseEntry(scanner.Text()); ok {
+			entries = append(entries, entry)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read known hosts '%s': %v", path, err)
+	}
+	return entries, nil
+}
+
+// ParseEntry parses a line of a known_hosts file. False is returned for comments, blank lines and invalid entries.
+func ParseEntry(line string) (Entry, bool) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return Entry{}, false
+	}
+
+	var entry Entry
+	if strings.HasPrefix(fields[0], "@") {
+		entry.Marker = fields[0]
+		fields = fields[1:]
+	}
+
+	if len(fields) < 3 {
+		return Entry{}, false
+	}
+
+	key, err := base64.StdEncoding.DecodeString(fields[2])
+	if err != nil {
+		return Entry{}, false
+	}
+
+	entry.Hosts = strings.Split(fields[0], ",")
+	entry.KeyType = fields[1]
+	entry.Key = key
+	return entry, true
+}
+
+// MatchHost returns true if the entry applies to host on port. Negated patterns take precedence over other patterns.
+func (e Entry) MatchHost(host string, port int) bool {
+	name := host
+	if port != DefaultSSHPort {
+		name = "[" + host + "]:" + strconv.Itoa(port)
+	}
+
+	matched := false
+	for _, pattern := range e.Hosts {
+		negated := strings.HasPrefix(pattern, "!")
+		if negated {
+			pattern = pattern[1:]
+		}
+
+		if !matchPattern(pattern, name) {
+			continue
+		} else if negated {
+			return false
+		}
+		matched = true
+	}
+	return matched
+}
+
+// MatchKey returns true if the hashes in key are those of the entry's key.
+func (e Entry) MatchKey(key HostKey) bool {
+	if len(key.SHA1) != 0 {
+		sum := sha1.Sum(e.Key)
+		return bytes.Equal(sum[:], key.SHA1)
+	}
+
+	sum := md5.Sum(e.Key)
+	return len(key.MD5) != 0 && bytes.Equal(sum[:], key.MD5)
+}
+
+// matchPattern matches name against a single host pattern, which may be hashed or contain the wildcards '*' and '?'.
+func matchPattern(pattern, name string) bool {
+	if strings.HasPrefix(pattern, hashedPrefix) {
+		return matchHashed(pattern, name)
+	}
+
+	return matchWildcard(strings.ToLower(pattern), strings.ToLower(name))
+}
+
+// matchWildcard matches name against pattern where '*' matches any sequence of characters and '?' matches a single
+// character. Unlike filepath.Match, brackets have no special meaning since they are used for hosts with ports.
+func matchWildcard(pattern, name string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(name); i >= 0; i-- {
+				if matchWildcard(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(name) == 0 {
+				return false
+			}
+		default:
+			if len(name) == 0 || pattern[0] != name[0] {
+				return false
+			}
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// matchHashed matches name against a pattern of the form |1|salt|hash where hash is HMAC-SHA1 of the name.
+func matchHashed(pattern, name string) bool {
+	parts := strings.Split(pattern[len(hashedPrefix):], "|")
+	if len(parts) != 2 {
+		return false
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(parts[0])
+	if err != nil {
+		return false
+	}
+
+	expected, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha1.New, salt)
+	mac.Write([]byte(strings.ToLower(name)))
+	return hmac.Equal(mac.Sum(nil), expected)
+}
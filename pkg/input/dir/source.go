@@ -64,7 +64,27 @@ func (fs FileSource) Entities(t entity.Type, objects ...deploy.KubeObject) ([]en
 func (fs FileSource) Objects() (objects []deploy.KubeObject, err error) {
 	dirPath := path.Join(string(fs), ObjectsDir)
 
-	err = walkPathForObjects(dirPath, func(info *resource.Info, walkErr error) error {
+	objects, err = ObjectsFromPath(dirPath, false)
+
+	// don't throw error if simply didn't find anything
+	if err != nil && !checkErrNoResources(err) && !checkErrPathDoesNotExist(err) && !strings.HasSuffix(err.Error(), "not a directory") {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// ObjectsFromPath returns the Kubernetes objects in the manifest file or directory at path, which doesn't need to follow
+// the Redspread convention. If path is "-", manifests are read from stdin. If recursive is true, manifests in
+// subdirectories of path are also read; hidden directories are skipped.
+func ObjectsFromPath(path string, recursive bool) (objects []deploy.KubeObject, err error) {
+	paths := []string{path}
+	if recursive && path != "-" {
+		if paths, err = withSubdirectories(path); err != nil {
+			return nil, err
+		}
+	}
+
+	err = walkPathsForObjects(paths, func(info *resource.Info, walkErr error) error {
 		if walkErr != nil {
 			return walkErr
 		}
@@ -74,12 +94,7 @@ func (fs FileSource) Objects() (objects []deploy.KubeObject, err error) {
 		objects = append(objects, obj)
 		return nil
 	})
-
-	// don't throw error if simply didn't find anything
-	if err != nil && !checkErrNoResources(err) && !checkErrPathDoesNotExist(err) && !strings.HasSuffix(err.Error(), "not a directory") {
-		return nil, err
-	}
-	return objects, nil
+	return objects, err
 }
 
 // rcs returns entities for the rcs in the RCFile
@@ -214,6 +229,10 @@ func (fs FileSource) containers(objects []deploy.KubeObject) (containers []entit
 }
 
 func walkPathForObjects(path string, fn resource.VisitorFunc) error {
+	return walkPathsForObjects([]string{path}, fn)
+}
+
+func walkPathsForObjects(paths []string, fn resource.VisitorFunc) error {
 	f := kubectl.NewFactory(nil)
 
 	// todo: does "cacheDir" need to be parameterizable?
@@ -226,7 +245,7 @@ func walkPathForObjects(path string, fn resource.VisitorFunc) error {
 	result := resource.NewBuilder(mapper, typer, resource.DisabledClientForMapping{}, f.Decoder(true)).
 		ContinueOnError().
 		Schema(schema).
-		FilenameParam(false, path).
+		FilenameParam(false, paths...).
 		Flatten().
 		Do()
 
@@ -242,6 +261,26 @@ func walkPathForObjects(path string, fn resource.VisitorFunc) error {
 	return nil
 }
 
+// withSubdirectories returns root along with every directory beneath it, except hidden directories.
+func withSubdirectories(root string) (paths []string, err error) {
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		} else if path == root {
+			paths = append(paths, path)
+			return nil
+		} else if !info.IsDir() {
+			return nil
+		} else if strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		paths = append(paths, path)
+		return nil
+	})
+	return
+}
+
 // withExtensions returns the inputted string with the supported file extensions appended to it
 func withExtensions(begin string) []string {
 	if len(ObjectExtensions) == 0 {
@@ -84,6 +84,60 @@ func TestSourceObjectsKubeDir(t *testing.T) {
 	}
 }
 
+func TestObjectsFromPath(t *testing.T) {
+	dir := testTempDir(t)
+	defer os.RemoveAll(dir)
+
+	numObjects := 3
+	expected := testRandomObjects(numObjects)
+	for _, v := range expected {
+		filename := path.Join(dir, v.GetObjectMeta().GetName()+".yaml")
+		testWriteYAMLToFile(t, filename, v)
+	}
+
+	actual, err := ObjectsFromPath(dir, false)
+	assert.NoError(t, err)
+	assert.Len(t, actual, numObjects, "all manifests in directory should be read")
+
+	single := path.Join(dir, expected[0].GetObjectMeta().GetName()+".yaml")
+	actual, err = ObjectsFromPath(single, false)
+	assert.NoError(t, err)
+	if assert.Len(t, actual, 1, "should read single manifest") {
+		assert.Equal(t, expected[0].GetObjectMeta().GetName(), actual[0].GetObjectMeta().GetName())
+	}
+
+	_, err = ObjectsFromPath(path.Join(dir, "missing.yaml"), false)
+	assert.Error(t, err, "missing path should error")
+}
+
+func TestObjectsFromPathRecursive(t *testing.T) {
+	dir := testTempDir(t)
+	defer os.RemoveAll(dir)
+
+	actual, err := ObjectsFromPath(dir, true)
+	assert.NoError(t, err, "an empty directory isn't an error")
+	assert.Len(t, actual, 0, "an empty directory has no objects")
+
+	subDirs := []string{dir, path.Join(dir, "a"), path.Join(dir, "a", "b"), path.Join(dir, ".hidden")}
+	expected := testRandomObjects(len(subDirs))
+	for i, v := range expected {
+		if err := os.MkdirAll(subDirs[i], TestFilePerms); err != nil {
+			t.Fatal(err)
+		}
+
+		filename := path.Join(subDirs[i], v.GetObjectMeta().GetName()+".yaml")
+		testWriteYAMLToFile(t, filename, v)
+	}
+
+	actual, err = ObjectsFromPath(dir, false)
+	assert.NoError(t, err)
+	assert.Len(t, actual, 1, "subdirectories shouldn't be read")
+
+	actual, err = ObjectsFromPath(dir, true)
+	assert.NoError(t, err)
+	assert.Len(t, actual, 3, "subdirectories other than hidden ones should be read")
+}
+
 // TODO: Add tests for entities in wrong folders
 
 func TestSourceInvalidEntity(t *testing.T) {
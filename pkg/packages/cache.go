@@ -0,0 +1,199 @@
+package packages
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+
+	"rsprd.com/spread/pkg/config"
+)
+
+const (
+	// CacheFileName is the name of the file within the global project that caches discovery results.
+	CacheFileName = "discovery.json"
+)
+
+// cacheEntry is a discovery result and the time it was retrieved.
+type cacheEntry struct {
+	Info    PackageInfo `json:"info"`
+	Fetched time.Time   `json:"fetched"`
+}
+
+// Cache stores the results of package discovery on disk.
+type Cache struct {
+	path    string
+	entries map[string]cacheEntry
+}
+
+// OpenCache reads the discovery cache at path. An empty Cache is returned if the file doesn't exist.
+func OpenCache(path string) (*Cache, error) {
+	cache := &Cache{
+		path:    path,
+		entries: map[string]cacheEntry{},
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cache, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read discovery cache: %v", err)
+	}
+
+	if err = json.Unmarshal(data, &cache.entries); err != nil {
+		return nil, fmt.Errorf("could not parse discovery cache '%s': %v", path, err)
+	}
+	return cache, nil
+}
+
+// Get returns the cached information for packageName and whether it was retrieved within ttl.
+func (c *Cache) Get(packageName string, ttl time.Duration) (info PackageInfo, fresh, ok bool) {
+	entry, ok := c.entries[packageName]
+	if !ok {
+		return PackageInfo{}, false, false
+	}
+	return entry.Info, time.Since(entry.Fetched) < ttl, true
+}
+
+// Put caches info for packageName.
+func (c *Cache) Put(packageName string, info PackageInfo) {
+	c.entries[packageName] = cacheEntry{
+		Info:    info,
+		Fetched: time.Now(),
+	}
+}
+
+// Write stores the Cache on disk.
+func (c *Cache) Write() error {
+	data, err := json.MarshalIndent(c.entries, "", "\t")
+	if err != nil {
+		return fmt.Errorf("could not encode discovery cache: %v", err)
+	}
+
+	if err = ioutil.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("could not write discovery cache: %v", err)
+	}
+	return nil
+}
+
+// Resolver discovers packages using configured mirrors and a cache before falling back to the network.
+type Resolver struct {
+	// Cache holds previous discovery results. If nil, nothing is cached.
+	Cache *Cache
+	// TTL is how long cached results are used before discovery is performed again.
+	TTL time.Duration
+	// Offline prevents network access. Only mirrors and cached results are used.
+	Offline bool
+	// Mirrors maps package name prefixes to alternative repository URLs or local paths.
+	Mirrors map[string]string
+	// Insecure allows discovery to fallback to HTTP.
+	Insecure bool
+	// Verbose will print information to config.Out.
+	Verbose bool
+}
+
+// NewResolver returns a Resolver configured with cfg that caches results in cache.
+func NewResolver(cfg config.PackageConfig, cache *Cache) (*Resolver, error) {
+	ttl, err := cfg.TTL()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Resolver{
+		Cache:    cache,
+		TTL:      ttl,
+		Offline:  cfg.Offline,
+		Mirrors:  cfg.Mirrors,
+		Insecure: cfg.InsecureDiscovery,
+	}, nil
+}
+
+// Discover returns the PackageInfo for packageName. Mirrors take precedence over cached results, which take
+// precedence over discovery. If discovery fails, an expired cache entry is used if available.
+func (r *Resolver) Discover(packageName string) (PackageInfo, error) {
+	if info, ok, err := r.mirror(packageName); ok || err != nil {
+		return info, err
+	}
+
+	var cached, fresh, ok bool
+	var info PackageInfo
+	if r.Cache != nil {
+		info, fresh, ok = r.Cache.Get(packageName, r.TTL)
+		cached = ok
+	}
+
+	if fresh || (cached && r.Offline) {
+		return info, nil
+	} else if r.Offline {
+		return PackageInfo{}, ErrNotCached
+	}
+
+	discovered, err := DiscoverPackage(packageName, r.Insecure, r.Verbose)
+	if err != nil {
+		if cached {
+			if r.Verbose {
+				fmt.Fprintf(config.Out, "discovery failed, using expired cache entry for '%s': %v\n", packageName, err)
+			}
+			return info, nil
+		}
+		return PackageInfo{}, err
+	}
+
+	if r.Cache != nil {
+		r.Cache.Put(packageName, discovered)
+		if err = r.Cache.Write(); err != nil {
+			return PackageInfo{}, err
+		}
+	}
+	return discovered, nil
+}
+
+// mirror returns information for packageName using the mirror with the longest matching prefix.
+func (r *Resolver) mirror(packageName string) (info PackageInfo, ok bool, err error) {
+	var prefix string
+	for p := range r.Mirrors {
+		if (packageName == p || strings.HasPrefix(packageName, p+"/")) && len(p) > len(prefix) {
+			prefix = p
+		}
+	}
+
+	if len(prefix) == 0 {
+		return PackageInfo{}, false, nil
+	}
+
+	repo := r.Mirrors[prefix]
+	if isLocalPath(repo) {
+		if repo, err = homedir.Expand(repo); err != nil {
+			return PackageInfo{}, true, err
+		}
+		if repo, err = filepath.Abs(repo); err != nil {
+			return PackageInfo{}, true, err
+		}
+	}
+
+	// packages under the prefix are stored in subpaths of the mirror
+	if rest := strings.TrimPrefix(packageName, prefix); len(rest) > 0 {
+		repo = strings.TrimSuffix(repo, "/") + rest
+	}
+
+	return PackageInfo{
+		Prefix:  packageName,
+		RepoURL: repo,
+	}, true, nil
+}
+
+// isLocalPath returns true if repo refers to a path on the local filesystem rather than a URL.
+func isLocalPath(repo string) bool {
+	return filepath.IsAbs(repo) || strings.HasPrefix(repo, "~") || strings.HasPrefix(repo, ".")
+}
+
+var (
+	// ErrNotCached is returned when discovery is required while offline.
+	ErrNotCached = errors.New("package has not been discovered before and offline mode is enabled")
+)
@@ -0,0 +1,91 @@
+package packages
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestResolverMirror(t *testing.T) {
+	r := &Resolver{
+		Offline: true,
+		Mirrors: map[string]string{
+			"redspread.com/library":            "https://git.example.com/library",
+			"redspread.com/library/mattermost": "/srv/spread/mattermost",
+		},
+	}
+
+	tests := []struct {
+		pkg, repo string
+	}{
+		{"redspread.com/library/hadoop", "https://git.example.com/library/hadoop"},
+		{"redspread.com/library/mattermost", "/srv/spread/mattermost"},
+		{"redspread.com/library/mattermost/db", "/srv/spread/mattermost/db"},
+	}
+
+	for i, test := range tests {
+		info, err := r.Discover(test.pkg)
+		if err != nil {
+			t.Errorf("test %d: could not discover '%s': %v", i, test.pkg, err)
+		} else if info.RepoURL != test.repo {
+			t.Errorf("test %d: expected '%s', got '%s'", i, test.repo, info.RepoURL)
+		}
+	}
+
+	// prefixes only match complete path segments
+	if _, err := r.Discover("redspread.com/libraryx/hadoop"); err != ErrNotCached {
+		t.Errorf("expected ErrNotCached, got %v", err)
+	}
+}
+
+func TestResolverCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spread-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	expected := PackageInfo{
+		Prefix:  "redspread.com/halp",
+		RepoURL: "http://104.155.154.203/test.git",
+	}
+	server := NewDServer(t, expected)
+	go server.Start()
+	defer server.Stop()
+
+	path := filepath.Join(dir, CacheFileName)
+	cache, err := OpenCache(path)
+	if err != nil {
+		t.Fatalf("could not open cache: %v", err)
+	}
+
+	pkg := fmt.Sprintf("%s/halp", server.Addr())
+	r := &Resolver{
+		Cache:    cache,
+		TTL:      time.Hour,
+		Insecure: true,
+	}
+
+	if info, err := r.Discover(pkg); err != nil {
+		t.Fatalf("could not discover package: %v", err)
+	} else if info != expected {
+		t.Errorf("expected %v, got %v", expected, info)
+	}
+
+	// reopen cache from disk and use it without network access
+	if r.Cache, err = OpenCache(path); err != nil {
+		t.Fatalf("could not reopen cache: %v", err)
+	}
+	server.Stop()
+	r.Offline = true
+	r.TTL = 0
+
+	if info, err := r.Discover(pkg); err != nil {
+		t.Errorf("offline discovery should use expired cache: %v", err)
+	} else if info != expected {
+		t.Errorf("expected %v, got %v", expected, info)
+	}
+}
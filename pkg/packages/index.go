@@ -0,0 +1,187 @@
+package packages
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// IndexFileName is the name of the file in a published index directory that lists the packages it contains.
+	IndexFileName = "spread-packages.json"
+
+	// pageFileName is the name of the HTML file written for each package.
+	pageFileName = "index.html"
+)
+
+var pageTemplate = template.Must(template.New("discovery").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+{{range .Refs}}<meta name="` + DiscoveryMetaName + `" content="{{.Prefix}} {{.RepoURL}}">
+{{end}}<title>{{.Title}}</title>
+</head>
+<body>
+<ul>
+{{range .Listed}}<li>{{.Prefix}}: <code>spread deploy {{.Prefix}}</code></li>
+{{end}}</ul>
+</body>
+</html>
+`))
+
+// page is the data used to render pageTemplate.
+type page struct {
+	Title string
+	// Refs are included as discovery information.
+	Refs []PackageInfo
+	// Listed are displayed in the body of the page.
+	Listed []PackageInfo
+}
+
+// Index is a set of packages published under a domain. It generates the pages used for discovery.
+type Index struct {
+	Packages []PackageInfo
+}
+
+// ReadIndex reads the index published in dir. An empty Index is returned if none exists.
+func ReadIndex(dir string) (*Index, error) {
+	index := new(Index)
+	data, err := ioutil.ReadFile(filepath.Join(dir, IndexFileName))
+	if os.IsNotExist(err) {
+		return index, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read index: %v", err)
+	}
+
+	if err = json.Unmarshal(data, &index.Packages); err != nil {
+		return nil, fmt.Errorf("could not parse index in '%s': %v", dir, err)
+	}
+	return index, nil
+}
+
+// Add adds info to the index, replacing any package with the same prefix.
+func (i *Index) Add(info PackageInfo) {
+	for n, pkg := range i.Packages {
+		if pkg.Prefix == info.Prefix {
+			i.Packages[n] = info
+			return
+		}
+	}
+
+	i.Packages = append(i.Packages, info)
+	sort.Sort(byPrefix(i.Packages))
+}
+
+// Write creates a static site in dir that can be used for discovery. A page is written for every package at the path
+// of its prefix without the domain; a page listing all packages is written at the root.
+func (i *Index) Write(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("could not create index directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(i.Packages, "", "\t")
+	if err != nil {
+		return fmt.Errorf("could not encode index: %v", err)
+	}
+
+	if err = ioutil.WriteFile(filepath.Join(dir, IndexFileName), data, 0644); err != nil {
+		return fmt.Errorf("could not write index: %v", err)
+	}
+
+	for _, pkg := range i.Packages {
+		pkgDir := filepath.Join(dir, filepath.FromSlash(prefixPath(pkg.Prefix)))
+		if err = writePage(pkgDir, i.packagePage(pkg)); err != nil {
+			return err
+		}
+	}
+
+	return writePage(dir, i.rootPage())
+}
+
+// Lookup returns the package that contains the package at path. Path is the package name without its domain.
+func (i *Index) Lookup(path string) (info PackageInfo, ok bool) {
+	path = strings.Trim(path, "/")
+	for _, pkg := range i.Packages {
+		prefix := prefixPath(pkg.Prefix)
+		if (path == prefix || strings.HasPrefix(path, prefix+"/")) && len(prefix) >= len(prefixPath(info.Prefix)) {
+			info, ok = pkg, true
+		}
+	}
+	return
+}
+
+// ServeHTTP responds to discovery requests with the page for the requested package. Requests to the root list every
+// package without discovery information.
+func (i *Index) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := i.rootPage()
+	if strings.Trim(r.URL.Path, "/") != "" {
+		pkg, ok := i.Lookup(r.URL.Path)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		p = i.packagePage(pkg)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := pageTemplate.Execute(w, p); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// packagePage returns the discovery page for pkg.
+func (i *Index) packagePage(pkg PackageInfo) page {
+	pkgs := []PackageInfo{pkg}
+	return page{
+		Title:  pkg.Prefix,
+		Refs:   pkgs,
+		Listed: pkgs,
+	}
+}
+
+// rootPage returns a page listing every package. Discovery information isn't included because only the first
+// reference found on a page is used.
+func (i *Index) rootPage() page {
+	return page{
+		Title:  "Spread packages",
+		Listed: i.Packages,
+	}
+}
+
+// writePage renders p to a file in dir.
+func writePage(dir string, p page) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("could not create directory for '%s': %v", p.Title, err)
+	}
+
+	f, err := os.Create(filepath.Join(dir, pageFileName))
+	if err != nil {
+		return fmt.Errorf("could not create page for '%s': %v", p.Title, err)
+	}
+	defer f.Close()
+
+	if err = pageTemplate.Execute(f, p); err != nil {
+		return fmt.Errorf("could not render page for '%s': %v", p.Title, err)
+	}
+	return nil
+}
+
+// prefixPath returns the path of a package prefix without its domain.
+func prefixPath(prefix string) string {
+	if i := strings.Index(prefix, "/"); i >= 0 {
+		return strings.Trim(prefix[i+1:], "/")
+	}
+	return ""
+}
+
+type byPrefix []PackageInfo
+
+func (p byPrefix) Len() int           { return len(p) }
+func (p byPrefix) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p byPrefix) Less(i, j int) bool { return p[i].Prefix < p[j].Prefix }
@@ -0,0 +1,80 @@
+package packages
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIndexWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spread-index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	expected := PackageInfo{
+		Prefix:  "spread.example.com/library/mattermost",
+		RepoURL: "https://git.example.com/mattermost.git",
+	}
+
+	index, err := ReadIndex(dir)
+	if err != nil {
+		t.Fatalf("could not read empty index: %v", err)
+	}
+	index.Add(expected)
+	if err = index.Write(dir); err != nil {
+		t.Fatalf("could not write index: %v", err)
+	}
+
+	f, err := os.Open(filepath.Join(dir, "library", "mattermost", pageFileName))
+	if err != nil {
+		t.Fatalf("page for package was not written: %v", err)
+	}
+	defer f.Close()
+
+	pkgs, err := parseSpreadRefs(f)
+	if err != nil {
+		t.Fatalf("could not parse page: %v", err)
+	} else if len(pkgs) != 1 || pkgs[0] != expected {
+		t.Errorf("expected %v, got %v", expected, pkgs)
+	}
+
+	read, err := ReadIndex(dir)
+	if err != nil {
+		t.Fatalf("could not read index: %v", err)
+	} else if len(read.Packages) != 1 || read.Packages[0] != expected {
+		t.Errorf("expected index to contain %v, got %v", expected, read.Packages)
+	}
+}
+
+func TestIndexServe(t *testing.T) {
+	index := new(Index)
+	server := httptest.NewServer(index)
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	expected := PackageInfo{
+		Prefix:  host + "/library",
+		RepoURL: "https://git.example.com/library.git",
+	}
+	index.Add(expected)
+	index.Add(PackageInfo{
+		Prefix:  host + "/other",
+		RepoURL: "https://git.example.com/other.git",
+	})
+
+	actual, err := DiscoverPackage(host+"/library/mattermost", true, false)
+	if err != nil {
+		t.Fatalf("could not discover package: %v", err)
+	} else if actual != expected {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	if _, err = DiscoverPackage(host+"/missing", true, false); err == nil {
+		t.Error("discovery of unpublished package should fail")
+	}
+}
@@ -0,0 +1,99 @@
+package packages
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+const (
+	// LockFileName is the name of the file within a project's Spread directory that pins packages to commits.
+	LockFileName = "packages.lock"
+)
+
+// LockedPackage records how a package was resolved.
+type LockedPackage struct {
+	// Name is the expanded name of the package without a version.
+	Name string `json:"name"`
+	// RepoURL is the location of the repository returned by discovery.
+	RepoURL string `json:"repo"`
+	// Version is the version that was requested. It is empty if no version was requested.
+	Version string `json:"version,omitempty"`
+	// Commit is the OID of the commit Version resolved to.
+	Commit string `json:"commit"`
+}
+
+// Lock is the set of packages pinned for a project.
+type Lock struct {
+	packages map[string]LockedPackage
+}
+
+// ReadLock reads the lock file at path. An empty Lock is returned if the file doesn't exist.
+func ReadLock(path string) (*Lock, error) {
+	lock := &Lock{
+		packages: map[string]LockedPackage{},
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return lock, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read lock file: %v", err)
+	}
+
+	var pkgs []LockedPackage
+	if err = json.Unmarshal(data, &pkgs); err != nil {
+		return nil, fmt.Errorf("could not parse lock file '%s': %v", path, err)
+	}
+
+	for _, pkg := range pkgs {
+		lock.packages[pkg.Name] = pkg
+	}
+	return lock, nil
+}
+
+// Write stores the Lock at path. Packages are written sorted by name to keep the file stable.
+func (l *Lock) Write(path string) error {
+	data, err := json.MarshalIndent(l.Packages(), "", "\t")
+	if err != nil {
+		return fmt.Errorf("could not encode lock file: %v", err)
+	}
+
+	if err = ioutil.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("could not write lock file: %v", err)
+	}
+	return nil
+}
+
+// Get returns the locked package with name.
+func (l *Lock) Get(name string) (pkg LockedPackage, ok bool) {
+	pkg, ok = l.packages[name]
+	return
+}
+
+// Set adds or replaces a locked package.
+func (l *Lock) Set(pkg LockedPackage) {
+	l.packages[pkg.Name] = pkg
+}
+
+// Remove deletes the package with name from the Lock.
+func (l *Lock) Remove(name string) {
+	delete(l.packages, name)
+}
+
+// Packages returns all locked packages sorted by name.
+func (l *Lock) Packages() []LockedPackage {
+	names := make([]string, 0, len(l.packages))
+	for name := range l.packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pkgs := make([]LockedPackage, len(names))
+	for i, name := range names {
+		pkgs[i] = l.packages[name]
+	}
+	return pkgs
+}
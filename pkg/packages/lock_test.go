@@ -0,0 +1,62 @@
+package packages
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLockRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spread-lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, LockFileName)
+	lock, err := ReadLock(path)
+	if err != nil {
+		t.Fatalf("missing lock file should not error: %v", err)
+	} else if len(lock.Packages()) != 0 {
+		t.Fatalf("expected empty lock, got %d packages", len(lock.Packages()))
+	}
+
+	expected := []LockedPackage{
+		{
+			Name:    "redspread.com/library/mattermost",
+			RepoURL: "https://github.com/redspread/mattermost",
+			Version: "^2",
+			Commit:  "3ff52b2a1ea3d6f0a8ef7ed4e2b3c4d5e6f7a8b9",
+		},
+		{
+			Name:    "redspread.com/library/hadoop",
+			RepoURL: "https://github.com/redspread/hadoop",
+			Commit:  "0a1b2c3d4e5f60718293a4b5c6d7e8f901234567",
+		},
+	}
+	for _, pkg := range expected {
+		lock.Set(pkg)
+	}
+
+	if err = lock.Write(path); err != nil {
+		t.Fatalf("could not write lock: %v", err)
+	}
+
+	read, err := ReadLock(path)
+	if err != nil {
+		t.Fatalf("could not read lock: %v", err)
+	}
+
+	// packages are sorted by name
+	expected[0], expected[1] = expected[1], expected[0]
+	if actual := read.Packages(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	read.Remove("redspread.com/library/hadoop")
+	if _, ok := read.Get("redspread.com/library/hadoop"); ok {
+		t.Error("package should have been removed")
+	}
+}
@@ -0,0 +1,201 @@
+package packages
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"rsprd.com/spread/pkg/config"
+)
+
+const (
+	// signatureHeader is the commit header that holds a signature.
+	signatureHeader = "gpgsig"
+
+	gpgSignaturePrefix = "-----BEGIN PGP SIGNATURE-----"
+	sshSignaturePrefix = "-----BEGIN SSH SIGNATURE-----"
+
+	// sshNamespace is the namespace Git uses when signing commits with SSH keys.
+	sshNamespace = "git"
+)
+
+// ExtractSignature separates the signature from a raw commit object. The payload is the commit without the signature,
+// which is the data that was signed. ErrUnsigned is returned if the commit has no signature.
+func ExtractSignature(rawCommit []byte) (signature, payload []byte, err error) {
+	var sig, out bytes.Buffer
+	inHeaders, inSig, found := true, false, false
+
+	lines := bytes.SplitAfter(rawCommit, []byte("\n"))
+	for _, line := range lines {
+		switch {
+		case !inHeaders:
+			out.Write(line)
+		case inSig && bytes.HasPrefix(line, []byte(" ")):
+			sig.Write(line[1:])
+		case bytes.HasPrefix(line, []byte(signatureHeader+" ")):
+			inSig, found = true, true
+			sig.Write(line[len(signatureHeader)+1:])
+		default:
+			inSig = false
+			if len(bytes.TrimSpace(line)) == 0 {
+				inHeaders = false
+			}
+			out.Write(line)
+		}
+	}
+
+	if !found {
+		return nil, nil, ErrUnsigned
+	}
+	return sig.Bytes(), out.Bytes(), nil
+}
+
+// Verifier checks commit signatures against a set of trusted keys.
+type Verifier struct {
+	// GPGKeys are the fingerprints or long key IDs of trusted GPG keys. The keys must be in the user's keyring.
+	GPGKeys []string
+	// SSHKeys are trusted SSH public keys in authorized_keys format.
+	SSHKeys []string
+	// GPGProgram is the gpg executable used for verification.
+	GPGProgram string
+	// SSHKeygenProgram is the ssh-keygen executable used for verification.
+	SSHKeygenProgram string
+}
+
+// NewVerifier returns a Verifier using the trust store in cfg. If no keys are trusted, no commit will verify.
+func NewVerifier(cfg config.TrustConfig) *Verifier {
+	v := &Verifier{
+		GPGKeys:          cfg.GPGKeys,
+		SSHKeys:          cfg.SSHKeys,
+		GPGProgram:       cfg.GPGProgram,
+		SSHKeygenProgram: "ssh-keygen",
+	}
+	if len(v.GPGProgram) == 0 {
+		v.GPGProgram = "gpg"
+	}
+	return v
+}
+
+// VerifyCommit checks that rawCommit is signed by a trusted key. The key that signed the commit is returned.
+func (v *Verifier) VerifyCommit(rawCommit []byte) (signer string, err error) {
+	sig, payload, err := ExtractSignature(rawCommit)
+	if err != nil {
+		return "", err
+	} else if len(v.GPGKeys) == 0 && len(v.SSHKeys) == 0 {
+		return "", ErrNoTrustedKeys
+	}
+
+	switch {
+	case bytes.HasPrefix(sig, []byte(gpgSignaturePrefix)):
+		return v.verifyGPG(sig, payload)
+	case bytes.HasPrefix(sig, []byte(sshSignaturePrefix)):
+		return v.verifySSH(sig, payload)
+	}
+	return "", errors.New("unknown signature format")
+}
+
+// verifyGPG verifies sig with gpg and checks the signing key against GPGKeys.
+func (v *Verifier) verifyGPG(sig, payload []byte) (string, error) {
+	sigFile, err := tempFile("spread-sig", sig)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(sigFile)
+
+	cmd := exec.Command(v.GPGProgram, "--status-fd=1", "--verify", sigFile, "-")
+	cmd.Stdin = bytes.NewReader(payload)
+	out, _ := cmd.Output()
+
+	// VALIDSIG <fingerprint> ... <primary key fingerprint>
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[0] != "[GNUPG:]" || fields[1] != "VALIDSIG" {
+			continue
+		}
+
+		fingerprints := []string{fields[2]}
+		if len(fields) > 11 {
+			fingerprints = append(fingerprints, fields[11])
+		}
+
+		for _, fpr := range fingerprints {
+			for _, key := range v.GPGKeys {
+				key = strings.ToUpper(strings.Replace(key, " ", "", -1))
+				if len(key) >= 16 && strings.HasSuffix(fpr, key) {
+					return fpr, nil
+				}
+			}
+		}
+		return "", fmt.Errorf("%v: signed by GPG key %s", ErrUntrusted, fields[2])
+	}
+	return "", fmt.Errorf("%v: GPG signature could not be verified", ErrUntrusted)
+}
+
+// verifySSH verifies sig with ssh-keygen using SSHKeys as the allowed signers.
+func (v *Verifier) verifySSH(sig, payload []byte) (string, error) {
+	if len(v.SSHKeys) == 0 {
+		return "", fmt.Errorf("%v: no SSH keys are trusted", ErrUntrusted)
+	}
+
+	var signers bytes.Buffer
+	for _, key := range v.SSHKeys {
+		fmt.Fprintf(&signers, "* namespaces=\"%s\" %s\n", sshNamespace, key)
+	}
+
+	signersFile, err := tempFile("spread-signers", signers.Bytes())
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(signersFile)
+
+	sigFile, err := tempFile("spread-sig", sig)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(sigFile)
+
+	cmd := exec.Command(v.SSHKeygenProgram, "-Y", "verify", "-f", signersFile, "-I", "spread", "-n", sshNamespace, "-s", sigFile)
+	cmd.Stdin = bytes.NewReader(payload)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%v: %s", ErrUntrusted, strings.TrimSpace(string(out)))
+	}
+
+	// Good "git" signature for spread with ED25519 key SHA256:...
+	if i := bytes.Index(out, []byte(" key ")); i >= 0 {
+		return strings.TrimSpace(string(out[i+len(" key "):])), nil
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// tempFile writes data to a new temporary file and returns its name.
+func tempFile(prefix string, data []byte) (string, error) {
+	f, err := ioutil.TempFile("", prefix)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err = f.Write(data); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+var (
+	// ErrUnsigned is returned when a package commit has no signature.
+	ErrUnsigned = errors.New("commit is not signed")
+
+	// ErrUntrusted is returned when a package commit isn't signed by a trusted key.
+	ErrUntrusted = errors.New("commit is not signed by a trusted key")
+
+	// ErrNoTrustedKeys is returned when verifying a commit without any trusted keys configured.
+	ErrNoTrustedKeys = errors.New("no keys are trusted to sign packages, add them to the trust section of your configuration")
+)
@@ -0,0 +1,89 @@
+package packages
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"rsprd.com/spread/pkg/config"
+)
+
+const (
+	testSignedCommit = `tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904
+author t <t@t> 1792394391 +0000
+committer t <t@t> 1792394391 +0000
+gpgsig -----BEGIN SSH SIGNATURE-----
+ U1NIU0lHAAAAAQAAADMAAAALc3NoLWVkMjU1MTkAAAAgXPUq03xUWCrvULcDQ/x8ejfMLv
+ TthLYZOjxehwWwcyMAAAADZ2l0AAAAAAAAAAZzaGE1MTIAAABTAAAAC3NzaC1lZDI1NTE5
+ AAAAQHfpNj3oPXMLYjKBpqJTIPd4g5RGIDtH2Pkd5FghrFrFfMRtS5KuLwpqwyKMaNE5vz
+ AL+g70LCUbmqDZkSJ82AI=
+ -----END SSH SIGNATURE-----
+
+signed
+`
+
+	testSignedPayload = `tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904
+author t <t@t> 1792394391 +0000
+committer t <t@t> 1792394391 +0000
+
+signed
+`
+
+	testSigningKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFz1KtN8VFgq71C3A0P8fHo3zC707YS2GTo8XocFsHMj test"
+	testOtherKey   = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBY6Yob0S8gc3OQ6Cly6L1eigGOdpM02IjhXwJ0P/Wd8 other"
+)
+
+func TestExtractSignature(t *testing.T) {
+	sig, payload, err := ExtractSignature([]byte(testSignedCommit))
+	if err != nil {
+		t.Fatalf("could not extract signature: %v", err)
+	}
+
+	if !strings.HasPrefix(string(sig), sshSignaturePrefix) || !strings.HasSuffix(string(sig), "-----END SSH SIGNATURE-----\n") {
+		t.Errorf("signature was not extracted correctly: %q", sig)
+	}
+
+	if string(payload) != testSignedPayload {
+		t.Errorf("expected payload %q, got %q", testSignedPayload, payload)
+	}
+
+	if _, _, err = ExtractSignature(payload); err != ErrUnsigned {
+		t.Errorf("expected ErrUnsigned, got %v", err)
+	}
+}
+
+func TestVerifyNoTrustedKeys(t *testing.T) {
+	v := NewVerifier(config.TrustConfig{})
+
+	if _, err := v.VerifyCommit([]byte(testSignedCommit)); err != ErrNoTrustedKeys {
+		t.Errorf("expected ErrNoTrustedKeys, got %v", err)
+	}
+
+	if _, err := v.VerifyCommit([]byte(testSignedPayload)); err != ErrUnsigned {
+		t.Errorf("expected ErrUnsigned, got %v", err)
+	}
+}
+
+func TestVerifySSH(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is not available")
+	}
+
+	v := &Verifier{
+		SSHKeys:          []string{testSigningKey},
+		SSHKeygenProgram: "ssh-keygen",
+	}
+
+	if _, err := v.VerifyCommit([]byte(testSignedCommit)); err != nil {
+		t.Errorf("commit signed by trusted key should verify: %v", err)
+	}
+
+	v.SSHKeys = []string{testOtherKey}
+	if _, err := v.VerifyCommit([]byte(testSignedCommit)); err == nil {
+		t.Error("commit signed by untrusted key should not verify")
+	}
+
+	if _, err := v.VerifyCommit([]byte(testSignedPayload)); err != ErrUnsigned {
+		t.Errorf("expected ErrUnsigned, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package packages
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver"
+)
+
+const (
+	// VersionSeparator separates a package name from the version requested (e.g. "mattermost@v2.1").
+	VersionSeparator = "@"
+)
+
+// SplitVersion separates packageName into the name of the package and the version requested. The version is empty
+// if none was given.
+func SplitVersion(packageName string) (name, version string) {
+	i := strings.LastIndex(packageName, VersionSeparator)
+	if i < 0 {
+		return packageName, ""
+	}
+	return packageName[:i], packageName[i+1:]
+}
+
+// IsVersionRange returns true if version should be interpreted as a semantic version range rather than as the name
+// of a tag, branch, or commit.
+func IsVersionRange(version string) bool {
+	return len(version) > 0 && strings.ContainsAny(version[:1], "^~<>=")
+}
+
+// VersionRange reports whether a version satisfies a constraint.
+type VersionRange func(v semver.Version) bool
+
+// ParseVersionRange parses a semantic version range. Supported are caret ("^2", "^1.4.0"), tilde ("~1.4"), and
+// comparison (">=1.2", "<2.0.0", "=1.3.1") constraints. Constraints separated by whitespace must all be satisfied.
+func ParseVersionRange(constraint string) (VersionRange, error) {
+	fields := strings.Fields(constraint)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty version range")
+	}
+
+	ranges := make([]VersionRange, len(fields))
+	for i, field := range fields {
+		r, err := parseComparator(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version range '%s': %v", constraint, err)
+		}
+		ranges[i] = r
+	}
+
+	return func(v semver.Version) bool {
+		for _, r := range ranges {
+			if !r(v) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// MatchVersion returns the tag with the highest version that satisfies constraint. Tags that are not semantic
+// versions (with an optional "v" prefix) and prerelease versions are ignored.
+func MatchVersion(constraint string, tags []string) (string, error) {
+	inRange, err := ParseVersionRange(constraint)
+	if err != nil {
+		return "", err
+	}
+
+	var best string
+	var bestVersion semver.Version
+	for _, tag := range tags {
+		v, err := semver.Parse(strings.TrimPrefix(tag, "v"))
+		if err != nil || len(v.Pre) > 0 || !inRange(v) {
+			continue
+		}
+
+		if len(best) == 0 || v.GT(bestVersion) {
+			best, bestVersion = tag, v
+		}
+	}
+
+	if len(best) == 0 {
+		return "", fmt.Errorf("no tag satisfies '%s'", constraint)
+	}
+	return best, nil
+}
+
+// parseComparator returns a VersionRange for a single constraint.
+func parseComparator(c string) (VersionRange, error) {
+	switch {
+	case strings.HasPrefix(c, "^"):
+		lower, parts, err := parsePartialVersion(c[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		var upper semver.Version
+		switch {
+		case lower.Major > 0 || parts == 1:
+			upper = semver.Version{Major: lower.Major + 1}
+		case lower.Minor > 0 || parts == 2:
+			upper = semver.Version{Minor: lower.Minor + 1}
+		default:
+			upper = semver.Version{Patch: lower.Patch + 1}
+		}
+		return between(lower, upper), nil
+
+	case strings.HasPrefix(c, "~"):
+		lower, parts, err := parsePartialVersion(c[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		upper := semver.Version{Major: lower.Major, Minor: lower.Minor + 1}
+		if parts == 1 {
+			upper = semver.Version{Major: lower.Major + 1}
+		}
+		return between(lower, upper), nil
+
+	case strings.HasPrefix(c, ">="):
+		v, _, err := parsePartialVersion(c[2:])
+		return func(o semver.Version) bool { return o.GTE(v) }, err
+
+	case strings.HasPrefix(c, "<="):
+		v, _, err := parsePartialVersion(c[2:])
+		return func(o semver.Version) bool { return o.LTE(v) }, err
+
+	case strings.HasPrefix(c, ">"):
+		v, _, err := parsePartialVersion(c[1:])
+		return func(o semver.Version) bool { return o.GT(v) }, err
+
+	case strings.HasPrefix(c, "<"):
+		v, _, err := parsePartialVersion(c[1:])
+		return func(o semver.Version) bool { return o.LT(v) }, err
+
+	case strings.HasPrefix(c, "="):
+		v, _, err := parsePartialVersion(c[1:])
+		return func(o semver.Version) bool { return o.EQ(v) }, err
+	}
+	return nil, fmt.Errorf("unknown operator in '%s'", c)
+}
+
+// between returns a range including lower and excluding upper.
+func between(lower, upper semver.Version) VersionRange {
+	return func(v semver.Version) bool {
+		return v.GTE(lower) && v.LT(upper)
+	}
+}
+
+// parsePartialVersion parses a version which may omit the minor and patch numbers. Omitted numbers are zero.
+// The number of parts given is returned.
+func parsePartialVersion(s string) (v semver.Version, parts int, err error) {
+	s = strings.TrimPrefix(s, "v")
+	split := strings.SplitN(s, ".", 3)
+	if len(split) == 3 {
+		v, err = semver.Parse(s)
+		return v, 3, err
+	}
+
+	nums := make([]uint64, 2)
+	for i, part := range split {
+		if nums[i], err = strconv.ParseUint(part, 10, 64); err != nil {
+			return v, 0, fmt.Errorf("'%s' is not a valid version", s)
+		}
+	}
+	return semver.Version{Major: nums[0], Minor: nums[1]}, len(split), nil
+}
@@ -0,0 +1,76 @@
+package packages
+
+import (
+	"testing"
+)
+
+func TestSplitVersion(t *testing.T) {
+	tests := []struct {
+		in, name, version string
+	}{
+		{"mattermost", "mattermost", ""},
+		{"redspread.com/library/mattermost@v2.1", "redspread.com/library/mattermost", "v2.1"},
+		{"library/mattermost@^2", "library/mattermost", "^2"},
+		{"mattermost@", "mattermost", ""},
+	}
+
+	for i, test := range tests {
+		name, version := SplitVersion(test.in)
+		if name != test.name {
+			t.Errorf("test %d: expected name '%s', got '%s'", i, test.name, name)
+		} else if version != test.version {
+			t.Errorf("test %d: expected version '%s', got '%s'", i, test.version, version)
+		}
+	}
+}
+
+var testTags = []string{"v1.0.0", "v1.4.2", "v2.0.0", "v2.1.0", "v2.1.3", "v2.2.0-beta", "2.3.0", "v3.0.0", "stable", "v0.2.1", "v0.2.5", "v0.3.0"}
+
+var matchTestData = []struct {
+	constraint string
+	tag        string
+	error      bool
+}{
+	{constraint: "^2", tag: "2.3.0"},
+	{constraint: "^2.1", tag: "2.3.0"},
+	{constraint: "^1", tag: "v1.4.2"},
+	{constraint: "^0.2", tag: "v0.2.5"},
+	{constraint: "^0.2.1", tag: "v0.2.5"},
+	{constraint: "~2.1", tag: "v2.1.3"},
+	{constraint: "~2", tag: "2.3.0"},
+	{constraint: ">=2.1 <2.2", tag: "v2.1.3"},
+	{constraint: "<2", tag: "v1.4.2"},
+	{constraint: "=v2.0.0", tag: "v2.0.0"},
+	{constraint: ">3", error: true},
+	{constraint: "^4", error: true},
+	{constraint: "^two", error: true},
+	{constraint: "!2", error: true},
+	{constraint: "", error: true},
+}
+
+func TestMatchVersion(t *testing.T) {
+	for i, test := range matchTestData {
+		tag, err := MatchVersion(test.constraint, testTags)
+		if err == nil && test.error {
+			t.Errorf("test %d (constraint: %s): should have errored, got '%s'", i, test.constraint, tag)
+		} else if err != nil && !test.error {
+			t.Errorf("test %d (constraint: %s) errored: %v", i, test.constraint, err)
+		} else if tag != test.tag {
+			t.Errorf("test %d (constraint: %s): expected '%s', got '%s'", i, test.constraint, test.tag, tag)
+		}
+	}
+}
+
+func TestIsVersionRange(t *testing.T) {
+	for _, v := range []string{"^2", "~1.4", ">=1.0", "<2", "=1.0.0"} {
+		if !IsVersionRange(v) {
+			t.Errorf("'%s' should be a version range", v)
+		}
+	}
+
+	for _, v := range []string{"", "v2.1", "master", "2a3f9c1"} {
+		if IsVersionRange(v) {
+			t.Errorf("'%s' should not be a version range", v)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package project
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+	git "gopkg.in/libgit2/git2go.v23"
+
+	"rsprd.com/spread/pkg/credentials"
+	"rsprd.com/spread/pkg/hostkeys"
+)
+
+// remoteOperation holds the state of a single push or fetch.
+type remoteOperation struct {
+	ctx      context.Context
+	remote   *git.Remote
+	creds    credentials.Provider
+	hostKeys *hostkeys.Checker
+	// progress is nil if progress isn't reported.
+	progress Progress
+
+	// iter is created when credentials are first requested, it advances every time authentication fails.
+	iter *credentials.Iterator
+	// err is the reason a callback aborted the operation.
+	err error
+}
+
+// newRemoteOperation returns the state for an operation on remote using the project's configuration. The operation
+// is aborted once ctx is cancelled.
+func (p *Project) newRemoteOperation(ctx context.Context, remote *git.Remote) *remoteOperation {
+	op := &remoteOperation{
+		ctx:      ctx,
+		remote:   remote,
+		creds:    p.Credentials,
+		hostKeys: p.HostKeys,
+		progress: p.Progress,
+	}
+
+	if op.creds == nil {
+		op.creds = credentials.Default()
+	}
+
+	if op.hostKeys == nil {
+		op.hostKeys = hostkeys.Default()
+	}
+	return op
+}
+
+// callbacks returns the libgit2 callbacks for the operation. Cancellation is only checked while objects are being
+// transferred; references are updated after the transfer and are never left partially updated.
+func (op *remoteOperation) callbacks() git.RemoteCallbacks {
+	return git.RemoteCallbacks{
+		CredentialsCallback:          op.credentials,
+		CertificateCheckCallback:     op.checkCertificate,
+		TransferProgressCallback:     op.transferProgress,
+		PushTransferProgressCallback: op.pushTransferProgress,
+		PackProgressCallback:         op.packProgress,
+		SidebandProgressCallback:     op.sidebandProgress,
+	}
+}
+
+// cause returns the error that made a callback abort the operation, or err if there is none.
+func (op *remoteOperation) cause(err error) error {
+	if op.err != nil {
+		return op.err
+	}
+	return err
+}
+
+// cancelled returns git.ErrUser if the operation's context has been cancelled.
+func (op *remoteOperation) cancelled() git.ErrorCode {
+	if err := op.ctx.Err(); err != nil {
+		op.err = err
+		return git.ErrUser
+	}
+	return git.ErrOk
+}
+
+// transferProgress reports objects received from the remote.
+func (op *remoteOperation) transferProgress(stats git.TransferProgress) git.ErrorCode {
+	if op.progress != nil {
+		op.progress.Transfer(TransferStats{
+			Direction:      Receiving,
+			Objects:        stats.ReceivedObjects,
+			TotalObjects:   stats.TotalObjects,
+			IndexedObjects: stats.IndexedObjects,
+			Bytes:          stats.ReceivedBytes,
+		})
+	}
+	return op.cancelled()
+}
+
+// pushTransferProgress reports objects sent to the remote.
+func (op *remoteOperation) pushTransferProgress(current, total uint32, bytes uint) git.ErrorCode {
+	if op.progress != nil {
+		op.progress.Transfer(TransferStats{
+			Direction:    Sending,
+			Objects:      uint(current),
+			TotalObjects: uint(total),
+			Bytes:        bytes,
+		})
+	}
+	return op.cancelled()
+}
+
+// packProgress is called while the pack for a push is built.
+func (op *remoteOperation) packProgress(stage int32, current, total uint32) git.ErrorCode {
+	return op.cancelled()
+}
+
+// sidebandProgress reports messages sent by the remote.
+func (op *remoteOperation) sidebandProgress(text string) git.ErrorCode {
+	if op.progress != nil {
+		op.progress.Message(text)
+	}
+	return op.cancelled()
+}
+
+// credentials returns the next candidate credential for the remote. Each time authentication fails, libgit2 asks
+// again and the next candidate is tried.
+func (op *remoteOperation) credentials(url string, username_from_url string, allowed_types git.CredType) (git.ErrorCode, *git.Cred) {
+	if op.iter == nil {
+		op.iter = credentials.NewIterator(op.creds, credentials.Request{
+			URL:      url,
+			Username: username_from_url,
+		})
+	}
+
+	for {
+		cred, ok := op.iter.Next()
+		if !ok {
+			if err := op.iter.Err(); err != nil {
+				op.err = fmt.Errorf("no valid credentials for '%s': %v", url, err)
+			} else {
+				op.err = fmt.Errorf("no valid credentials for '%s'", url)
+			}
+			return git.ErrAuth, nil
+		}
+
+		if gitCred, ok := toGitCred(cred, allowed_types); ok {
+			return git.ErrOk, gitCred
+		}
+	}
+}
+
+// checkCertificate verifies the SSH host key or TLS certificate presented by the remote, unless the remote has been
+// configured as insecure.
+func (op *remoteOperation) checkCertificate(cert *git.Certificate, valid bool, hostname string) git.ErrorCode {
+	if op.hostKeys.IsInsecure(op.remote.Name(), op.remote.Url()) {
+		return git.ErrOk
+	}
+
+	var err error
+	switch cert.Kind {
+	case git.CertificateHostkey:
+		err = op.hostKeys.VerifyHostKey(hostname, hostkeys.Port(op.remote.Url()), hostKey(cert.Hostkey))
+	case git.CertificateX509:
+		err = op.hostKeys.VerifyCertificate(hostname, cert.X509, valid)
+	default:
+		err = fmt.Errorf("unknown certificate type presented by '%s'", hostname)
+	}
+
+	if err != nil {
+		op.err = err
+		return git.ErrUser
+	}
+	return git.ErrOk
+}
+
+// hostKey converts the hashes of an SSH host key provided by libgit2.
+func hostKey(cert git.HostkeyCertificate) hostkeys.HostKey {
+	var key hostkeys.HostKey
+	if cert.Kind&git.HostkeyMD5 != 0 {
+		key.MD5 = cert.HashMD5[:]
+	}
+
+	if cert.Kind&git.HostkeySHA1 != 0 {
+		key.SHA1 = cert.HashSHA1[:]
+	}
+	return key
+}
+
+// toGitCred converts cred for use by libgit2. False is returned if cred isn't one of the allowed types or can't be
+// used.
+func toGitCred(cred credentials.Credential, allowed git.CredType) (*git.Cred, bool) {
+	var code int
+	var gitCred git.Cred
+	switch {
+	case cred.Kind == credentials.SSHKey && allowed&git.CredTypeSshKey != 0:
+		if cred.Agent {
+			code, gitCred = git.NewCredSshKeyFromAgent(cred.Username)
+			break
+		}
+
+		passphrase := ""
+		if cred.Passphrase != nil {
+			var err error
+			if passphrase, err = cred.Passphrase(); err != nil {
+				return nil, false
+			}
+		}
+		code, gitCred = git.NewCredSshKey(cred.Username, cred.PublicKey, cred.PrivateKey, passphrase)
+	case cred.Kind == credentials.UserPass && allowed&git.CredTypeUserpassPlaintext != 0:
+		code, gitCred = git.NewCredUserpassPlaintext(cred.Username, cred.Password)
+	default:
+		return nil, false
+	}
+
+	if git.ErrorCode(code) != git.ErrOk {
+		return nil, false
+	}
+	return &gitCred, true
+}
@@ -0,0 +1,175 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/context"
+	git "gopkg.in/libgit2/git2go.v23"
+)
+
+const (
+	// DefaultRemote is the name given to the remote a project was cloned from.
+	DefaultRemote = "origin"
+
+	// defaultBranch is preferred when the remote's HEAD can't be determined.
+	defaultBranch = "master"
+)
+
+// Clone creates a new project at target from the Spread repository at url. The repository is added as DefaultRemote
+// and its default branch is used for HEAD and to populate the index. If configure is not nil, it is called before
+// anything is fetched so credentials and other remote settings can be set. Target, and any of its parents that were
+// created for it, are removed if the clone fails.
+func Clone(ctx context.Context, url, target string, configure func(*Project) error) (proj *Project, err error) {
+	created, err := missingDir(target)
+	if err != nil {
+		return nil, err
+	}
+
+	proj, err = InitProject(target)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			os.RemoveAll(created)
+			proj = nil
+		}
+	}()
+
+	if configure != nil {
+		if err = configure(proj); err != nil {
+			return
+		}
+	}
+
+	remote, err := proj.Remotes().Create(DefaultRemote, url)
+	if err != nil {
+		return nil, fmt.Errorf("could not create remote: %v", err)
+	}
+
+	if err = proj.fetch(ctx, remote, git.DownloadTagsAll); err != nil {
+		return
+	}
+
+	branch, err := remoteDefaultBranch(remote)
+	if err != nil {
+		return
+	} else if len(branch) == 0 {
+		// remote is empty
+		return proj, nil
+	}
+
+	if err = proj.checkoutRemoteBranch(DefaultRemote, branch); err != nil {
+		return
+	}
+	return proj, nil
+}
+
+// missingDir returns the outermost directory of path that doesn't exist, which is the first directory created when
+// making path.
+func missingDir(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	missing := path
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if _, err = os.Stat(dir); err == nil {
+			return missing, nil
+		} else if !os.IsNotExist(err) {
+			return "", err
+		} else if dir == filepath.Dir(dir) {
+			return dir, nil
+		}
+		missing = dir
+	}
+}
+
+// remoteDefaultBranch returns the name of the branch that HEAD points to on a remote that has been fetched from.
+// If HEAD isn't advertised, defaultBranch or the first branch is used. An empty string is returned if the remote has
+// no branches.
+func remoteDefaultBranch(remote *git.Remote) (string, error) {
+	heads, err := remote.Ls()
+	if err != nil {
+		return "", fmt.Errorf("could not list references of '%s': %v", remote.Name(), err)
+	}
+
+	var headID *git.Oid
+	var branches, matching []string
+	for _, head := range heads {
+		if head.Name == "HEAD" {
+			headID = head.Id
+		} else if strings.HasPrefix(head.Name, branchRef) {
+			branches = append(branches, head.Name)
+		}
+	}
+
+	for _, head := range heads {
+		if headID != nil && strings.HasPrefix(head.Name, branchRef) && head.Id.Equal(headID) {
+			matching = append(matching, head.Name)
+		}
+	}
+
+	if len(matching) != 0 {
+		branches = matching
+	}
+
+	for _, name := range branches {
+		if name == branchRef+defaultBranch {
+			return defaultBranch, nil
+		}
+	}
+
+	if len(branches) == 0 {
+		return "", nil
+	}
+	return strings.TrimPrefix(branches[0], branchRef), nil
+}
+
+// checkoutRemoteBranch creates a local branch tracking branch of remoteName, points HEAD to it, and replaces the
+// index with the branch's contents.
+func (p *Project) checkoutRemoteBranch(remoteName, branch string) error {
+	ref, err := p.repo.References.Lookup(remoteBranchRef + remoteName + "/" + branch)
+	if err != nil {
+		return fmt.Errorf("could not find branch '%s' of '%s': %v", branch, remoteName, err)
+	}
+
+	obj, err := ref.Peel(git.ObjectCommit)
+	if err != nil {
+		return err
+	}
+	commit := obj.(*git.Commit)
+
+	local, err := p.repo.CreateBranch(branch, commit, false)
+	if err != nil {
+		return fmt.Errorf("could not create branch '%s': %v", branch, err)
+	}
+
+	if err = local.SetUpstream(remoteName + "/" + branch); err != nil {
+		return fmt.Errorf("could not set upstream of '%s': %v", branch, err)
+	}
+
+	if err = p.repo.SetHead(branchRef + branch); err != nil {
+		return fmt.Errorf("could not set HEAD: %v", err)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return err
+	}
+
+	index, err := p.repo.Index()
+	if err != nil {
+		return fmt.Errorf("could not retrieve index: %v", err)
+	}
+
+	if err = index.ReadTree(tree); err != nil {
+		return fmt.Errorf("could not populate index: %v", err)
+	}
+	return index.Write()
+}
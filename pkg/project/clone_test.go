@@ -0,0 +1,70 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+
+	"rsprd.com/spread/pkg/data"
+)
+
+func TestClone(t *testing.T) {
+	srcDir := filepath.Join(testDir, "cloneSource")
+	defer os.RemoveAll(srcDir)
+
+	src, err := InitProject(srcDir)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	doc, err := data.CreateDocument("test", "test/doc", map[string]interface{}{"key": "value"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, src.AddDocumentToIndex(doc))
+
+	person := Person{Name: "test", Email: "test@test.com", When: time.Now()}
+	_, err = src.Commit("HEAD", person, person, "initial")
+	assert.NoError(t, err)
+
+	target := filepath.Join(testDir, "cloneTarget")
+	defer os.RemoveAll(target)
+
+	url := filepath.Join(src.Path, GitDirectory)
+	proj, err := Clone(context.Background(), url, target, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	remote, err := proj.Remotes().Lookup(DefaultRemote)
+	if assert.NoError(t, err) {
+		assert.Equal(t, url, remote.Url())
+	}
+
+	head, err := proj.Head()
+	assert.NoError(t, err)
+	assert.Contains(t, head, "test/doc")
+
+	index, err := proj.Index()
+	assert.NoError(t, err)
+	assert.Contains(t, index, "test/doc")
+
+	_, err = Clone(context.Background(), url, target, nil)
+	assert.Error(t, err, "cloning into an existing project should fail")
+}
+
+func TestCloneCleanup(t *testing.T) {
+	dir := filepath.Join(testDir, "failedClone")
+	target := filepath.Join(dir, SpreadDirectory)
+	defer os.RemoveAll(dir)
+
+	_, err := Clone(context.Background(), filepath.Join(testDir, "does-not-exist"), target, nil)
+	assert.Error(t, err, "cloning a missing repository should fail")
+
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err), "directories created for the clone should be removed")
+}
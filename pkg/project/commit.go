@@ -62,21 +62,107 @@ func (p *Project) Head() (map[string]*pb.Document, error) {
 }
 
 func (p *Project) ResolveCommit(revision string) (map[string]*pb.Document, error) {
+	commit, err := p.lookupCommit(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.mapFromTree(tree)
+}
+
+// CommitID returns the full OID of the commit specified by revision.
+func (p *Project) CommitID(revision string) (string, error) {
+	commit, err := p.lookupCommit(revision)
+	if err != nil {
+		return "", err
+	}
+	return commit.Id().String(), nil
+}
+
+// CommitMessage returns the message of the commit specified by revision.
+func (p *Project) CommitMessage(revision string) (string, error) {
+	commit, err := p.lookupCommit(revision)
+	if err != nil {
+		return "", err
+	}
+	return commit.Message(), nil
+}
+
+// LatestTag returns the name of the most recent tag reachable from the commit specified by revision, like
+// `git describe --tags --abbrev=0`.
+func (p *Project) LatestTag(revision string) (string, error) {
+	commit, err := p.lookupCommit(revision)
+	if err != nil {
+		return "", err
+	}
+
+	opts, err := git.DefaultDescribeOptions()
+	if err != nil {
+		return "", err
+	}
+	opts.Strategy = git.DescribeTags
+
+	result, err := commit.Describe(&opts)
+	if err != nil {
+		return "", fmt.Errorf("could not find a tag for '%s': %v", revision, err)
+	}
+	defer result.Free()
+
+	formatOpts, err := git.DefaultDescribeFormatOptions()
+	if err != nil {
+		return "", err
+	}
+	formatOpts.AbbreviatedSize = 0
+	return result.Format(&formatOpts)
+}
+
+// RawCommit returns the contents of the commit object specified by revision, including any signature.
+func (p *Project) RawCommit(revision string) ([]byte, error) {
+	commit, err := p.lookupCommit(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	odb, err := p.repo.Odb()
+	if err != nil {
+		return nil, fmt.Errorf("could not open object database: %v", err)
+	}
+
+	obj, err := odb.Read(commit.Id())
+	if err != nil {
+		return nil, fmt.Errorf("could not read commit: %v", err)
+	}
+	defer obj.Free()
+
+	// copy data since it is owned by libgit2
+	data := make([]byte, len(obj.Data()))
+	copy(data, obj.Data())
+	return data, nil
+}
+
+// lookupCommit returns the commit specified by revision. Annotated tags are peeled to the commit they point to.
+func (p *Project) lookupCommit(revision string) (*git.Commit, error) {
 	gitObj, err := p.repo.RevparseSingle(revision)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't resolve revspec '%s': %v", revision, err)
 	}
 
-	if gitObj.Type() != git.ObjectCommit {
+	switch gitObj.Type() {
+	case git.ObjectCommit, git.ObjectTag:
+	default:
 		return nil, fmt.Errorf("'%s' specifies an object other than a commit", revision)
 	}
 
-	tree, err := gitObj.Peel(git.ObjectTree)
+	commit, err := gitObj.Peel(git.ObjectCommit)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("'%s' does not point to a commit: %v", revision, err)
 	}
-
-	return p.mapFromTree(tree.(*git.Tree))
+	return commit.(*git.Commit), nil
 }
 
 func (p *Project) headCommit() (*git.Commit, error) {
@@ -6,6 +6,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	git "gopkg.in/libgit2/git2go.v23"
 
+	"rsprd.com/spread/pkg/secrets"
 	pb "rsprd.com/spread/pkg/spreadproto"
 )
 
@@ -22,6 +23,18 @@ func (p *Project) GetDocument(revision, path string) (*pb.Document, error) {
 	return doc, nil
 }
 
+// GetDecryptedDocument returns the document at path in revision. If it is an encrypted Secret, its data is decrypted
+// using Identity.
+func (p *Project) GetDecryptedDocument(revision, path string) (*pb.Document, error) {
+	doc, err := p.GetDocument(revision, path)
+	if err != nil {
+		return nil, err
+	} else if err = p.decryptSecrets(doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
 func (p *Project) getDocument(oid *git.Oid) (*pb.Document, error) {
 	blob, err := p.repo.LookupBlob(oid)
 	if err != nil {
@@ -36,7 +49,12 @@ func (p *Project) getDocument(oid *git.Oid) (*pb.Document, error) {
 	return doc, nil
 }
 
+// createDocument stores obj as a blob. The data of Secret documents is encrypted for the project's recipients.
 func (p *Project) createDocument(obj *pb.Document) (oid *git.Oid, size int, err error) {
+	if obj, err = p.encryptSecrets(obj); err != nil {
+		return
+	}
+
 	data, err := proto.Marshal(obj)
 	if err != nil {
 		err = fmt.Errorf("could not encode document: %v", err)
@@ -51,3 +69,43 @@ func (p *Project) createDocument(obj *pb.Document) (oid *git.Oid, size int, err
 	}
 	return
 }
+
+// encryptSecrets returns a copy of doc with the data of Secrets encrypted for the project's recipients. Other documents
+// are returned unchanged. If there are no recipients, Secrets are only written unencrypted if PlaintextSecrets is set.
+func (p *Project) encryptSecrets(doc *pb.Document) (*pb.Document, error) {
+	if !secrets.IsSecretDocument(doc) {
+		return doc, nil
+	}
+
+	recipients, err := p.Recipients()
+	if err != nil {
+		return nil, err
+	} else if len(recipients) == 0 && p.PlaintextSecrets {
+		return doc, nil
+	} else if len(recipients) == 0 {
+		return nil, fmt.Errorf("could not write '%s': %v", doc.GetInfo().Path, ErrNoSecretRecipients)
+	}
+
+	doc = proto.Clone(doc).(*pb.Document)
+	if err = secrets.EncryptDocument(doc, recipients.Keys()); err != nil {
+		return nil, fmt.Errorf("could not encrypt '%s': %v", doc.GetInfo().Path, err)
+	}
+	return doc, nil
+}
+
+// decryptSecrets decrypts the data of doc in place if it is an encrypted Secret.
+func (p *Project) decryptSecrets(doc *pb.Document) error {
+	if !secrets.IsEncrypted(doc) {
+		return nil
+	} else if p.Identity == nil {
+		return fmt.Errorf("could not decrypt '%s': %v", doc.GetInfo().Path, ErrNoIdentity)
+	}
+
+	id, err := p.Identity()
+	if err != nil {
+		return fmt.Errorf("could not decrypt '%s': %v", doc.GetInfo().Path, err)
+	} else if err = secrets.DecryptDocument(doc, id); err != nil {
+		return fmt.Errorf("could not decrypt '%s': %v", doc.GetInfo().Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	git "gopkg.in/libgit2/git2go.v23"
+
+	"rsprd.com/spread/pkg/secrets"
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+// ImportStat lists the paths of documents changed by an import compared to HEAD.
+type ImportStat struct {
+	Added    []string
+	Modified []string
+	Removed  []string
+}
+
+// Empty returns true if the import didn't change any documents.
+func (s ImportStat) Empty() bool {
+	return len(s.Added) == 0 && len(s.Modified) == 0 && len(s.Removed) == 0
+}
+
+// ImportDocuments replaces the contents of the index with docs. Documents in HEAD that are not part of docs are
+// removed. The changes compared to HEAD are returned. Secrets are encrypted with new randomness each time they are
+// written, so a staged or committed copy of a Secret is kept if it decrypts to the same contents. Importing no
+// documents is refused since it would remove everything.
+func (p *Project) ImportDocuments(docs ...*pb.Document) (ImportStat, error) {
+	if len(docs) == 0 {
+		return ImportStat{}, ErrNoDocuments
+	}
+
+	head, err := p.headEntries()
+	if err != nil {
+		return ImportStat{}, err
+	}
+
+	index, err := p.repo.Index()
+	if err != nil {
+		return ImportStat{}, fmt.Errorf("could not retrieve index: %v", err)
+	}
+
+	staged, err := stagedEntries(index)
+	if err != nil {
+		return ImportStat{}, err
+	}
+
+	if err = index.RemoveAll([]string{"*"}, nil); err != nil {
+		return ImportStat{}, fmt.Errorf("could not clear index: %v", err)
+	}
+
+	var stat ImportStat
+	imported := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		info := doc.GetInfo()
+		if info == nil {
+			return ImportStat{}, ErrNilObjectInfo
+		}
+
+		path := info.Path
+		if imported[path] {
+			return ImportStat{}, fmt.Errorf("multiple objects have the path '%s'", path)
+		}
+		imported[path] = true
+
+		// the staged copy is preferred since it is encrypted for the current recipients
+		oid := p.sameSecret(doc, staged[path], head[path])
+		if oid != nil {
+			err = p.addStoredToIndex(index, path, oid)
+		} else {
+			oid, err = p.addToIndex(index, doc)
+		}
+		if err != nil {
+			return ImportStat{}, err
+		}
+
+		if headOid, exists := head[path]; !exists {
+			stat.Added = append(stat.Added, path)
+		} else if !headOid.Equal(oid) {
+			stat.Modified = append(stat.Modified, path)
+		}
+	}
+
+	for path := range head {
+		if !imported[path] {
+			stat.Removed = append(stat.Removed, path)
+		}
+	}
+
+	sort.Strings(stat.Added)
+	sort.Strings(stat.Modified)
+	sort.Strings(stat.Removed)
+	return stat, index.Write()
+}
+
+// sameSecret returns the first of oids which is an encrypted Secret with the same contents as doc once decrypted. Nil
+// is returned if none match or they can't be decrypted.
+func (p *Project) sameSecret(doc *pb.Document, oids ...*git.Oid) *git.Oid {
+	if !secrets.IsSecretDocument(doc) || p.Identity == nil {
+		return nil
+	}
+
+	for _, oid := range oids {
+		if oid == nil {
+			continue
+		}
+
+		stored, err := p.getDocument(oid)
+		if err != nil || !secrets.IsEncrypted(stored) {
+			continue
+		}
+
+		if err = p.decryptSecrets(stored); err == nil && proto.Equal(stored, doc) {
+			return oid
+		}
+	}
+	return nil
+}
+
+// addStoredToIndex adds an entry to index for the document already stored as oid. The index is not written.
+func (p *Project) addStoredToIndex(index *git.Index, path string, oid *git.Oid) error {
+	blob, err := p.repo.LookupBlob(oid)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve Document blob: %v", err)
+	}
+
+	entry := &git.IndexEntry{
+		Mode: git.FilemodeBlob,
+		Size: uint32(blob.Size()),
+		Id:   oid,
+		Path: path,
+	}
+	return index.Add(entry)
+}
+
+// stagedEntries returns the OIDs of the documents in index keyed by path.
+func stagedEntries(index *git.Index) (map[string]*git.Oid, error) {
+	count := index.EntryCount()
+	entries := make(map[string]*git.Oid, count)
+	for i := uint(0); i < count; i++ {
+		entry, err := index.EntryByIndex(i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve index entry: %v", err)
+		}
+		entries[entry.Path] = entry.Id
+	}
+	return entries, nil
+}
+
+// headEntries returns the OIDs of the documents in HEAD keyed by path. No entries are returned if HEAD doesn't exist.
+func (p *Project) headEntries() (map[string]*git.Oid, error) {
+	entries := map[string]*git.Oid{}
+	tree, err := p.resetTree("")
+	if err != nil || tree == nil {
+		return entries, err
+	}
+
+	err = tree.Walk(func(dir string, entry *git.TreeEntry) int {
+		if entry.Type == git.ObjectBlob {
+			entries[dir+entry.Name] = entry.Id
+		}
+		return 0
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error starting walk: %v", err)
+	}
+	return entries, nil
+}
+
+var (
+	ErrNoDocuments = errors.New("there are no documents to import")
+)
@@ -0,0 +1,144 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"rsprd.com/spread/pkg/data"
+	"rsprd.com/spread/pkg/secrets"
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+func testImportDoc(t *testing.T, path, value string) *pb.Document {
+	doc, err := data.CreateDocument(filepath.Base(path), path, map[string]interface{}{"key": value})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestImportDocuments(t *testing.T) {
+	target := filepath.Join(testDir, "importTest")
+	defer os.RemoveAll(target)
+
+	proj, err := InitProject(target)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// without commits everything is added
+	stat, err := proj.ImportDocuments(testImportDoc(t, "a", "1"), testImportDoc(t, "b", "1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, stat.Added)
+
+	person := Person{Name: "test", Email: "test@test.com", When: time.Now()}
+	_, err = proj.Commit("HEAD", person, person, "initial")
+	assert.NoError(t, err)
+
+	// staged changes are replaced by the import
+	addTestDoc(t, proj, "d", "1")
+
+	stat, err = proj.ImportDocuments(testImportDoc(t, "b", "2"), testImportDoc(t, "c", "1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c"}, stat.Added)
+	assert.Equal(t, []string{"b"}, stat.Modified)
+	assert.Equal(t, []string{"a"}, stat.Removed)
+
+	index, err := proj.Index()
+	assert.NoError(t, err)
+	assert.Len(t, index, 2)
+	assert.Contains(t, index, "b")
+	assert.Contains(t, index, "c")
+
+	_, err = proj.ImportDocuments(testImportDoc(t, "a", "1"), testImportDoc(t, "a", "2"))
+	assert.Error(t, err, "duplicate paths should fail")
+}
+
+func TestImportDocumentsEmpty(t *testing.T) {
+	target := filepath.Join(testDir, "importEmptyTest")
+	defer os.RemoveAll(target)
+
+	proj, err := InitProject(target)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	addTestDoc(t, proj, "a", "1")
+	person := Person{Name: "test", Email: "test@test.com", When: time.Now()}
+	_, err = proj.Commit("HEAD", person, person, "initial")
+	assert.NoError(t, err)
+
+	// an empty directory must not remove the whole project
+	_, err = proj.ImportDocuments()
+	assert.Equal(t, ErrNoDocuments, err)
+
+	index, err := proj.Index()
+	assert.NoError(t, err)
+	assert.Contains(t, index, "a", "the index shouldn't be cleared")
+}
+
+func TestImportDocumentsUnchanged(t *testing.T) {
+	target := filepath.Join(testDir, "importUnchangedTest")
+	defer os.RemoveAll(target)
+
+	proj, err := InitProject(target)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	addTestDoc(t, proj, "a", "1")
+	person := Person{Name: "test", Email: "test@test.com", When: time.Now()}
+	_, err = proj.Commit("HEAD", person, person, "initial")
+	assert.NoError(t, err)
+
+	stat, err := proj.ImportDocuments(testImportDoc(t, "a", "1"))
+	assert.NoError(t, err)
+	assert.True(t, stat.Empty())
+}
+
+func TestImportDocumentsUnchangedSecret(t *testing.T) {
+	target := filepath.Join(testDir, "importSecretTest")
+	defer os.RemoveAll(target)
+
+	proj, err := InitProject(target)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	id, err := secrets.GenerateIdentity()
+	if !assert.NoError(t, err) {
+		return
+	}
+	proj.Identity = func() (*secrets.Identity, error) {
+		return id, nil
+	}
+
+	recipients := secrets.Recipients{{Name: "test", Key: id.Public}}
+	assert.NoError(t, recipients.Write(proj.RecipientsPath()))
+
+	secret := func() *pb.Document {
+		obj := map[string]interface{}{"data": map[string]interface{}{"password": "aHVudGVyMg=="}}
+		doc, err := data.CreateDocument("db", "namespaces/default/secret/db", obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return doc
+	}
+
+	stat, err := proj.ImportDocuments(secret())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"namespaces/default/secret/db"}, stat.Added)
+
+	person := Person{Name: "test", Email: "test@test.com", When: time.Now()}
+	_, err = proj.Commit("HEAD", person, person, "initial")
+	assert.NoError(t, err)
+
+	// encrypting again produces different ciphertext, the contents must be compared instead
+	stat, err = proj.ImportDocuments(secret())
+	assert.NoError(t, err)
+	assert.True(t, stat.Empty(), "unchanged Secrets shouldn't be modified")
+}
@@ -3,6 +3,7 @@ package project
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	git "gopkg.in/libgit2/git2go.v23"
 
@@ -10,14 +11,37 @@ import (
 )
 
 func (p *Project) AddDocumentToIndex(doc *pb.Document) error {
-	oid, size, err := p.createDocument(doc)
+	return p.AddDocumentsToIndex(doc)
+}
+
+// AddDocumentsToIndex stages docs in the index. The index is written to disk once, after all documents have been
+// added.
+func (p *Project) AddDocumentsToIndex(docs ...*pb.Document) error {
+	index, err := p.repo.Index()
 	if err != nil {
-		return fmt.Errorf("object couldn't be created: %v", err)
+		return fmt.Errorf("could not retreive index: %v", err)
 	}
 
+	for _, doc := range docs {
+		if _, err = p.addToIndex(index, doc); err != nil {
+			return err
+		}
+	}
+
+	return index.Write()
+}
+
+// addToIndex stores doc in the repository and adds an entry for it to index. The OID of the stored document is
+// returned. The index is not written.
+func (p *Project) addToIndex(index *git.Index, doc *pb.Document) (*git.Oid, error) {
 	info := doc.GetInfo()
 	if info == nil {
-		return ErrNilObjectInfo
+		return nil, ErrNilObjectInfo
+	}
+
+	oid, size, err := p.createDocument(doc)
+	if err != nil {
+		return nil, fmt.Errorf("object couldn't be created: %v", err)
 	}
 
 	entry := &git.IndexEntry{
@@ -26,18 +50,7 @@ func (p *Project) AddDocumentToIndex(doc *pb.Document) error {
 		Id:   oid,
 		Path: info.Path,
 	}
-
-	index, err := p.repo.Index()
-	if err != nil {
-		return fmt.Errorf("could not retreive index: %v", err)
-	}
-
-	err = index.Add(entry)
-	if err != nil {
-		return err
-	}
-
-	return index.Write()
+	return oid, index.Add(entry)
 }
 
 func (p *Project) Index() (docs map[string]*pb.Document, err error) {
@@ -86,6 +99,176 @@ func (p *Project) DocFromIndex(path string) (*pb.Document, error) {
 	return nil, fmt.Errorf("could not find document with path '%s'", path)
 }
 
+// RemoveFromIndex removes the document at path from the index. If path is a directory, every document within it is
+// removed. The paths of removed documents are returned.
+func (p *Project) RemoveFromIndex(path string) ([]string, error) {
+	index, err := p.repo.Index()
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve index: %v", err)
+	}
+
+	removed, err := removeIndexEntries(index, []string{path})
+	if err != nil {
+		return nil, err
+	} else if len(removed) == 0 {
+		return nil, fmt.Errorf("could not find document with path '%s'", path)
+	}
+
+	return removed, index.Write()
+}
+
+// ResetIndex sets the documents in the index at paths to their state in revision. Documents that don't exist in
+// revision are removed from the index. If no paths are given, the entire index is reset. If revision is empty, HEAD is
+// used; in a project without commits the index is emptied.
+func (p *Project) ResetIndex(revision string, paths ...string) error {
+	tree, err := p.resetTree(revision)
+	if err != nil {
+		return err
+	}
+
+	index, err := p.repo.Index()
+	if err != nil {
+		return fmt.Errorf("could not retrieve index: %v", err)
+	}
+
+	if len(paths) == 0 {
+		if tree != nil {
+			err = index.ReadTree(tree)
+		} else {
+			err = index.RemoveAll([]string{"*"}, nil)
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not reset index: %v", err)
+		}
+		return index.Write()
+	}
+
+	if _, err = removeIndexEntries(index, paths); err != nil {
+		return err
+	}
+
+	if tree != nil {
+		var walkErr error
+		err = tree.Walk(func(dir string, entry *git.TreeEntry) int {
+			path := dir + entry.Name
+			if entry.Type != git.ObjectBlob || !matchesPaths(path, paths) {
+				return 0
+			}
+
+			blob, err := p.repo.LookupBlob(entry.Id)
+			if err != nil {
+				walkErr = err
+				return -1
+			}
+
+			walkErr = index.Add(&git.IndexEntry{
+				Mode: entry.Filemode,
+				Size: uint32(blob.Size()),
+				Id:   entry.Id,
+				Path: path,
+			})
+			if walkErr != nil {
+				return -1
+			}
+			return 0
+		})
+
+		if err != nil {
+			return fmt.Errorf("error starting walk: %v", err)
+		} else if walkErr != nil {
+			return fmt.Errorf("could not reset index: %v", walkErr)
+		}
+	}
+	return index.Write()
+}
+
+// RestoreIndex sets the documents in the index at paths to their state in revision. Unlike ResetIndex, paths must
+// be given and each must contain a document in revision. If revision is empty, HEAD is used.
+func (p *Project) RestoreIndex(revision string, paths ...string) error {
+	if len(paths) == 0 {
+		return ErrNoPaths
+	} else if len(revision) == 0 {
+		revision = "HEAD"
+	}
+
+	docs, err := p.ResolveCommit(revision)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		found := false
+		for docPath := range docs {
+			if matchesPaths(docPath, []string{path}) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("could not find document with path '%s' in %s", path, revision)
+		}
+	}
+	return p.ResetIndex(revision, paths...)
+}
+
+// resetTree returns the tree of revision. Nil is returned if revision is empty and HEAD doesn't exist yet.
+func (p *Project) resetTree(revision string) (*git.Tree, error) {
+	var commit *git.Commit
+	var err error
+	if len(revision) == 0 {
+		if commit, err = p.headCommit(); err != nil {
+			if git.IsErrorCode(err, git.ErrUnbornBranch) || git.IsErrorCode(err, git.ErrNotFound) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("could not retrieve head: %v", err)
+		}
+	} else if commit, err = p.lookupCommit(revision); err != nil {
+		return nil, err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("could not get tree for '%s': %v", revision, err)
+	}
+	return tree, nil
+}
+
+// removeIndexEntries removes the entries of index matching paths and returns their paths.
+func removeIndexEntries(index *git.Index, paths []string) ([]string, error) {
+	var matched []string
+	for i := uint(0); i < index.EntryCount(); i++ {
+		entry, err := index.EntryByIndex(i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve index entry: %v", err)
+		}
+
+		if matchesPaths(entry.Path, paths) {
+			matched = append(matched, entry.Path)
+		}
+	}
+
+	for _, path := range matched {
+		if err := index.RemoveByPath(path); err != nil {
+			return nil, fmt.Errorf("could not remove '%s' from index: %v", path, err)
+		}
+	}
+	return matched, nil
+}
+
+// matchesPaths returns true if path is one of paths or is within a directory in paths.
+func matchesPaths(path string, paths []string) bool {
+	for _, p := range paths {
+		p = strings.TrimSuffix(p, "/")
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 var (
 	ErrNilObjectInfo = errors.New("an object's Info field cannot be nil")
+	ErrNoPaths       = errors.New("at least one path must be specified")
 )
@@ -0,0 +1,122 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"rsprd.com/spread/pkg/data"
+)
+
+func addTestDoc(t *testing.T, proj *Project, path, value string) {
+	doc, err := data.CreateDocument(filepath.Base(path), path, map[string]interface{}{"key": value})
+	if assert.NoError(t, err) {
+		assert.NoError(t, proj.AddDocumentToIndex(doc))
+	}
+}
+
+func TestRemoveFromIndex(t *testing.T) {
+	target := filepath.Join(testDir, "removeTest")
+	defer os.RemoveAll(target)
+
+	proj, err := InitProject(target)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	addTestDoc(t, proj, "namespaces/default/service/web", "a")
+	addTestDoc(t, proj, "namespaces/default/service/db", "b")
+	addTestDoc(t, proj, "namespaces/other/service/web", "c")
+
+	removed, err := proj.RemoveFromIndex("namespaces/default/service/web")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"namespaces/default/service/web"}, removed)
+
+	removed, err = proj.RemoveFromIndex("namespaces/other/")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"namespaces/other/service/web"}, removed)
+
+	_, err = proj.RemoveFromIndex("namespaces/default/service/missing")
+	assert.Error(t, err, "removing a missing document should fail")
+
+	index, err := proj.Index()
+	assert.NoError(t, err)
+	assert.Len(t, index, 1)
+	assert.Contains(t, index, "namespaces/default/service/db")
+}
+
+func TestResetIndex(t *testing.T) {
+	target := filepath.Join(testDir, "resetTest")
+	defer os.RemoveAll(target)
+
+	proj, err := InitProject(target)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// without commits, reset empties the index
+	addTestDoc(t, proj, "a", "1")
+	assert.NoError(t, proj.ResetIndex(""))
+	index, err := proj.Index()
+	assert.NoError(t, err)
+	assert.Len(t, index, 0)
+
+	addTestDoc(t, proj, "a", "1")
+	addTestDoc(t, proj, "b", "1")
+	person := Person{Name: "test", Email: "test@test.com", When: time.Now()}
+	_, err = proj.Commit("HEAD", person, person, "initial")
+	assert.NoError(t, err)
+
+	addTestDoc(t, proj, "a", "2")
+	addTestDoc(t, proj, "b", "2")
+	addTestDoc(t, proj, "c", "2")
+
+	// reset single paths
+	assert.NoError(t, proj.ResetIndex("", "a", "c"))
+	index, err = proj.Index()
+	assert.NoError(t, err)
+	assert.Len(t, index, 2)
+	assert.NotContains(t, index, "c")
+
+	head, err := proj.Head()
+	assert.NoError(t, err)
+	assert.Equal(t, head["a"], index["a"])
+	assert.NotEqual(t, head["b"], index["b"])
+
+	// reset entire index
+	assert.NoError(t, proj.ResetIndex("HEAD"))
+	index, err = proj.Index()
+	assert.NoError(t, err)
+	assert.Equal(t, head, index)
+}
+
+func TestRestoreIndex(t *testing.T) {
+	target := filepath.Join(testDir, "restoreTest")
+	defer os.RemoveAll(target)
+
+	proj, err := InitProject(target)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	addTestDoc(t, proj, "dir/a", "1")
+	person := Person{Name: "test", Email: "test@test.com", When: time.Now()}
+	_, err = proj.Commit("HEAD", person, person, "initial")
+	assert.NoError(t, err)
+
+	_, err = proj.RemoveFromIndex("dir/a")
+	assert.NoError(t, err)
+	addTestDoc(t, proj, "b", "2")
+
+	assert.Error(t, proj.RestoreIndex(""), "paths are required")
+	assert.Error(t, proj.RestoreIndex("", "b"), "paths that aren't in the commit can't be restored")
+
+	assert.NoError(t, proj.RestoreIndex("", "dir"))
+	index, err := proj.Index()
+	assert.NoError(t, err)
+	assert.Len(t, index, 2)
+	assert.Contains(t, index, "dir/a")
+}
@@ -0,0 +1,41 @@
+package project
+
+// Direction is the direction of a transfer with a remote.
+type Direction int
+
+const (
+	// Receiving is used for transfers from a remote, such as fetches.
+	Receiving Direction = iota
+	// Sending is used for transfers to a remote, such as pushes.
+	Sending
+)
+
+// TransferStats describes the progress of a transfer with a remote.
+type TransferStats struct {
+	Direction Direction
+	// Objects is the number of objects transferred so far.
+	Objects uint
+	// TotalObjects is the number of objects that will be transferred.
+	TotalObjects uint
+	// IndexedObjects is the number of received objects that have been indexed. It is only set when Receiving.
+	IndexedObjects uint
+	// Bytes is the amount of data transferred so far.
+	Bytes uint
+}
+
+// Done returns true if all objects have been transferred.
+func (s TransferStats) Done() bool {
+	if s.Direction == Receiving {
+		return s.IndexedObjects == s.TotalObjects
+	}
+	return s.Objects == s.TotalObjects
+}
+
+// Progress receives updates during operations on remotes. Methods are called from the goroutine performing the
+// operation.
+type Progress interface {
+	// Transfer is called as objects are transferred.
+	Transfer(stats TransferStats)
+	// Message is called with text sent by the remote, such as "Counting objects". Text may contain partial lines.
+	Message(text string)
+}
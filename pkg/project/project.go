@@ -8,6 +8,11 @@ import (
 	"path/filepath"
 
 	git "gopkg.in/libgit2/git2go.v23"
+
+	"rsprd.com/spread/pkg/credentials"
+	"rsprd.com/spread/pkg/hostkeys"
+	"rsprd.com/spread/pkg/packages"
+	"rsprd.com/spread/pkg/secrets"
 )
 
 const (
@@ -20,7 +25,19 @@ const (
 
 type Project struct {
 	Path string
-	repo *git.Repository
+	// Credentials supplies credentials for remotes. If nil, credentials.Default() is used.
+	Credentials credentials.Provider
+	// HostKeys verifies the identity of remotes. If nil, hostkeys.Default() is used.
+	HostKeys *hostkeys.Checker
+	// Progress receives updates during operations on remotes. If nil, progress isn't reported.
+	Progress Progress
+	// PlaintextSecrets allows Secret documents to be stored unencrypted if no recipients are configured. Otherwise
+	// writing a Secret without recipients fails.
+	PlaintextSecrets bool
+	// Identity returns the identity used to decrypt Secret documents when their contents must be compared. If nil,
+	// encrypted Secrets are always treated as changed.
+	Identity func() (*secrets.Identity, error)
+	repo     *git.Repository
 }
 
 // InitProject creates a new Spread project including initializing a Git repository on disk.
@@ -58,7 +75,7 @@ func InitProject(target string) (*Project, error) {
 
 	// Create .gitignore file in directory ignoring Git repository
 	ignoreName := filepath.Join(target, ".gitignore")
-	ignoreData := fmt.Sprintf("/%s", GitDirectory)
+	ignoreData := fmt.Sprintf("/%s\n/%s\n", GitDirectory, secrets.StoreFileName)
 	ioutil.WriteFile(ignoreName, []byte(ignoreData), 0755)
 	return &Project{
 		Path: target,
@@ -92,10 +109,37 @@ func OpenProject(target string) (*Project, error) {
 	}, nil
 }
 
+// SecretStorePath returns the location of the project's store of encrypted secrets.
+func (p *Project) SecretStorePath() string {
+	return filepath.Join(p.Path, secrets.StoreFileName)
+}
+
+// RecipientsPath returns the location of the file listing who Secret documents are encrypted for.
+func (p *Project) RecipientsPath() string {
+	return filepath.Join(p.Path, secrets.RecipientsFileName)
+}
+
+// Recipients returns who Secret documents are encrypted for. Recipients aren't versioned, so each copy of a project
+// must configure its own.
+func (p *Project) Recipients() (secrets.Recipients, error) {
+	return secrets.ReadRecipients(p.RecipientsPath())
+}
+
+// LockPath returns the location of the file used to pin the packages used by the project.
+func (p *Project) LockPath() string {
+	return filepath.Join(p.Path, packages.LockFileName)
+}
+
 var (
 	// ErrEmptyPath is returned when a target string is empty.
 	ErrEmptyPath = errors.New("path must be specified")
 
 	// ErrPathNotDir is returned when a target is a file and is expected to be a directory.
 	ErrPathNotDir = errors.New("a directory must be specified")
+
+	// ErrNoSecretRecipients is returned when writing a Secret document without recipients to encrypt it for.
+	ErrNoSecretRecipients = errors.New("no recipients to encrypt Secret documents for, add one with 'spread secrets recipients add' or set plaintextSecrets in your configuration")
+
+	// ErrNoIdentity is returned when decrypting a Secret document without an Identity.
+	ErrNoIdentity = errors.New("no identity to decrypt Secret documents with")
 )
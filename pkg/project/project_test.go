@@ -4,9 +4,14 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	git "gopkg.in/libgit2/git2go.v23"
+
+	"rsprd.com/spread/pkg/data"
+	"rsprd.com/spread/pkg/secrets"
+	pb "rsprd.com/spread/pkg/spreadproto"
 )
 
 const (
@@ -74,3 +79,110 @@ func checkPath(t *testing.T, target string, dir bool) {
 		assert.True(t, fileInfo.IsDir() == dir, "should have created directory at %s", target)
 	}
 }
+
+func TestSecretRecipients(t *testing.T) {
+	target := filepath.Join(testDir, "secretTest")
+	defer os.RemoveAll(target)
+
+	proj, err := InitProject(target)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	secret := map[string]interface{}{"data": map[string]interface{}{"password": "aHVudGVyMg=="}}
+	doc, err := data.CreateDocument("db", "namespaces/default/secret/db", secret)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// a clone or any copy without recipients must not store Secrets in plaintext
+	err = proj.AddDocumentToIndex(doc)
+	if assert.Error(t, err, "Secrets shouldn't be written without recipients") {
+		assert.Contains(t, err.Error(), ErrNoSecretRecipients.Error())
+	}
+
+	proj.PlaintextSecrets = true
+	if assert.NoError(t, proj.AddDocumentToIndex(doc)) {
+		stored, err := proj.DocFromIndex("namespaces/default/secret/db")
+		assert.NoError(t, err)
+		assert.False(t, secrets.IsEncrypted(stored), "plaintext was explicitly allowed")
+	}
+	proj.PlaintextSecrets = false
+
+	id, err := secrets.GenerateIdentity()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	recipients := secrets.Recipients{{Name: "test", Key: id.Public}}
+	assert.NoError(t, recipients.Write(proj.RecipientsPath()))
+	if assert.NoError(t, proj.AddDocumentToIndex(doc)) {
+		stored, err := proj.DocFromIndex("namespaces/default/secret/db")
+		assert.NoError(t, err)
+		assert.True(t, secrets.IsEncrypted(stored), "Secrets should be encrypted for recipients")
+		assert.False(t, secrets.IsEncrypted(doc), "the document being added shouldn't be modified")
+	}
+}
+
+func TestLinkToSecret(t *testing.T) {
+	target := filepath.Join(testDir, "secretLinkTest")
+	defer os.RemoveAll(target)
+
+	proj, err := InitProject(target)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	id, err := secrets.GenerateIdentity()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	recipients := secrets.Recipients{{Name: "test", Key: id.Public}}
+	assert.NoError(t, recipients.Write(proj.RecipientsPath()))
+
+	secret := map[string]interface{}{"data": map[string]interface{}{"password": "aHVudGVyMg=="}}
+	doc, err := data.CreateDocument("db", "namespaces/default/secret/db", secret)
+	if !assert.NoError(t, err) || !assert.NoError(t, proj.AddDocumentToIndex(doc)) {
+		return
+	}
+
+	person := Person{Name: "test", Email: "test@test.com", When: time.Now()}
+	commit, err := proj.Commit("HEAD", person, person, "add secret")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	root, err := data.FieldFromJSON("", `{"password":null}`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	web := &pb.Document{Name: "web", Info: &pb.DocumentInfo{Path: "namespaces/default/pod/web"}, Root: root}
+
+	linkTarget, err := data.ParseSRI(commit + "/namespaces/default/secret/db?data.password")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	attach := &data.SRI{Treeish: "*", Path: web.Info.Path, Field: "password"}
+	if !assert.NoError(t, data.CreateLinkInDocument(web, data.NewLink("", linkTarget, false), attach)) {
+		return
+	}
+
+	docs := map[string]*pb.Document{web.Info.Path: web}
+	lookup := func(sri *data.SRI) (*pb.Document, error) {
+		return proj.GetDecryptedDocument(sri.Treeish, sri.Path)
+	}
+
+	_, err = proj.GetDecryptedDocument(commit, "namespaces/default/secret/db")
+	assert.Error(t, err, "Secrets can't be decrypted without an identity")
+
+	proj.Identity = func() (*secrets.Identity, error) {
+		return id, nil
+	}
+	if assert.NoError(t, data.ResolveLinks(docs, lookup)) {
+		field, err := data.GetFieldFromDocument(web, "password")
+		assert.NoError(t, err)
+		assert.Equal(t, "aHVudGVyMg==", field.GetStr(), "links should resolve to decrypted values")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"golang.org/x/net/context"
 	git "gopkg.in/libgit2/git2go.v23"
 )
 
@@ -11,10 +12,15 @@ const (
 	branchRef = "refs/heads/"
 )
 
-// Pull fetches refspec from remoteName and merges them on top of HEAD.
-func (p *Project) Pull(remoteName, refspec string) error {
+// Pull fetches refspec from remoteName and merges them on top of HEAD. If ctx is cancelled during the fetch, the pull
+// is aborted before any changes are merged.
+func (p *Project) Pull(ctx context.Context, remoteName, refspec string) error {
 	// fetch from remote
-	if err := p.Fetch(remoteName, refspec); err != nil {
+	if err := p.Fetch(ctx, remoteName, refspec); err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
 		return err
 	}
 
@@ -2,84 +2,172 @@ package project
 
 import (
 	"fmt"
+	"regexp"
 
-	"github.com/mitchellh/go-homedir"
-
+	"golang.org/x/net/context"
 	git "gopkg.in/libgit2/git2go.v23"
 )
 
-var remoteCallbacks = git.RemoteCallbacks{
-	CredentialsCallback: func(url string, username_from_url string, allowed_types git.CredType) (git.ErrorCode, *git.Cred) {
-		pubKey, err := homedir.Expand("~/.ssh/id_rsa.pub")
-		if err != nil {
-			return git.ErrAuth, nil
-		}
+const (
+	remoteBranchRef = "refs/remotes/"
+	remoteTagRef    = "refs/remote-tags/"
+)
 
-		privKey, err := homedir.Expand("~/.ssh/id_rsa")
-		if err != nil {
-			return git.ErrAuth, nil
-		}
-
-		code, key := git.NewCredSshKey("git", pubKey, privKey, "")
-		return git.ErrorCode(code), &key
-	},
-	CertificateCheckCallback: func(cert *git.Certificate, valid bool, hostname string) git.ErrorCode {
-		if cert.Kind == git.CertificateHostkey {
-			return git.ErrOk
-		} else if valid {
-			return git.ErrOk
-		}
-		return git.ErrAuth
-	},
-}
+var commitIDRegexp = regexp.MustCompile("^[0-9a-f]{4,40}$")
 
 func (p *Project) Remotes() *git.RemoteCollection {
 	return &p.repo.Remotes
 }
 
-func (p *Project) Push(remoteName string, refspecs ...string) error {
+// Push pushes refspecs to remoteName. The push is aborted if ctx is cancelled.
+func (p *Project) Push(ctx context.Context, remoteName string, refspecs ...string) error {
 	remote, err := p.Remotes().Lookup(remoteName)
 	if err != nil {
 		return fmt.Errorf("Failed to lookup branch: %v", err)
 	}
 
+	op := p.newRemoteOperation(ctx, remote)
 	opts := &git.PushOptions{
-		RemoteCallbacks: remoteCallbacks,
+		RemoteCallbacks: op.callbacks(),
 	}
 	err = remote.Push(refspecs, opts)
 	if err != nil {
-		return fmt.Errorf("Failed to push: %v", err)
+		return fmt.Errorf("Failed to push: %v", op.cause(err))
 	}
 	return nil
 }
 
-func (p *Project) Fetch(remoteName string, refspecs ...string) error {
+// PushTags pushes every tag in the repository to remoteName.
+func (p *Project) PushTags(ctx context.Context, remoteName string) error {
+	tags, err := p.repo.Tags.List()
+	if err != nil {
+		return fmt.Errorf("could not list tags: %v", err)
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	refspecs := make([]string, len(tags))
+	for i, tag := range tags {
+		refspecs[i] = tagRef + tag
+	}
+	return p.Push(ctx, remoteName, refspecs...)
+}
+
+// Fetch fetches refspecs from remoteName. The fetch is aborted if ctx is cancelled.
+func (p *Project) Fetch(ctx context.Context, remoteName string, refspecs ...string) error {
+	remote, err := p.Remotes().Lookup(remoteName)
+	if err != nil {
+		return fmt.Errorf("Failed to lookup remote: %v", err)
+	}
+
+	return p.fetch(ctx, remote, git.DownloadTagsUnspecified, refspecs...)
+}
+
+// FetchAll fetches every branch and tag from remoteName. Tags are stored in a namespace for the remote so that tags
+// of different remotes don't conflict with each other or with local tags.
+func (p *Project) FetchAll(ctx context.Context, remoteName string) error {
 	remote, err := p.Remotes().Lookup(remoteName)
 	if err != nil {
 		return fmt.Errorf("Failed to lookup remote: %v", err)
 	}
 
-	return p.fetch(remote, refspecs...)
+	branches := fmt.Sprintf("+%s*:%s%s/*", branchRef, remoteBranchRef, remoteName)
+	tags := fmt.Sprintf("+%s*:%s%s/*", tagRef, remoteTagRef, remoteName)
+	return p.fetch(ctx, remote, git.DownloadTagsNone, branches, tags)
+}
+
+// ResolveRemoteRevision returns a revision for version using the data retrieved from remoteName by FetchAll.
+// Version is matched against tags first, then branches, and finally commit IDs.
+func (p *Project) ResolveRemoteRevision(remoteName, version string) (string, error) {
+	candidates := []string{
+		remoteTagRef + remoteName + "/" + version,
+		remoteBranchRef + remoteName + "/" + version,
+	}
+
+	for _, name := range candidates {
+		if _, err := p.repo.References.Lookup(name); err == nil {
+			return name, nil
+		}
+	}
+
+	if commitIDRegexp.MatchString(version) {
+		// the repository may hold commits fetched from other remotes
+		if commit, err := p.lookupCommit(version); err == nil {
+			reachable, err := p.reachableFromRemote(remoteName, commit.Id())
+			if err != nil {
+				return "", err
+			} else if reachable {
+				return commit.Id().String(), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("could not find a tag, branch, or commit matching '%s' from '%s'", version, remoteName)
+}
+
+// reachableFromRemote returns true if the commit id can be reached from a branch or tag retrieved from remoteName by
+// FetchAll.
+func (p *Project) reachableFromRemote(remoteName string, id *git.Oid) (bool, error) {
+	for _, prefix := range []string{remoteBranchRef, remoteTagRef} {
+		iter, err := p.repo.NewReferenceIteratorGlob(prefix + remoteName + "/*")
+		if err != nil {
+			return false, fmt.Errorf("could not list references for '%s': %v", remoteName, err)
+		}
+
+		reachable, err := p.iterReachable(iter, id)
+		iter.Free()
+		if err != nil || reachable {
+			return reachable, err
+		}
+	}
+	return false, nil
+}
+
+// iterReachable returns true if the commit id is pointed to by, or an ancestor of, a reference from iter.
+func (p *Project) iterReachable(iter *git.ReferenceIterator, id *git.Oid) (bool, error) {
+	for {
+		ref, err := iter.Next()
+		if git.IsErrorCode(err, git.ErrIterOver) {
+			return false, nil
+		} else if err != nil {
+			return false, fmt.Errorf("could not list references: %v", err)
+		}
+
+		tip, err := ref.Peel(git.ObjectCommit)
+		if err != nil {
+			// references to objects other than commits can't contain id
+			continue
+		}
+
+		if tip.Id().Equal(id) {
+			return true, nil
+		} else if descendant, err := p.repo.DescendantOf(tip.Id(), id); err == nil && descendant {
+			return true, nil
+		}
+	}
 }
 
-func (p *Project) FetchAnonymous(url string, refspecs ...string) error {
+func (p *Project) FetchAnonymous(ctx context.Context, url string, refspecs ...string) error {
 	remote, err := p.Remotes().CreateAnonymous(url)
 	if err != nil {
 		return fmt.Errorf("Failed to create anonymous remote for '%s': %v", url, err)
 	}
 
-	return p.fetch(remote, refspecs...)
+	return p.fetch(ctx, remote, git.DownloadTagsUnspecified, refspecs...)
 }
 
-func (p *Project) fetch(remote *git.Remote, refspecs ...string) (err error) {
+func (p *Project) fetch(ctx context.Context, remote *git.Remote, tags git.DownloadTags, refspecs ...string) (err error) {
+	op := p.newRemoteOperation(ctx, remote)
 	opts := &git.FetchOptions{
-		RemoteCallbacks: remoteCallbacks,
+		RemoteCallbacks: op.callbacks(),
+		DownloadTags:    tags,
 	}
 
 	// fetch with default reflog message
 	err = remote.Fetch(refspecs, opts, "")
 	if err != nil {
-		return fmt.Errorf("Failed to fetch: %v", err)
+		return fmt.Errorf("Failed to fetch: %v", op.cause(err))
 	}
 	return
 }
@@ -0,0 +1,115 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	git "gopkg.in/libgit2/git2go.v23"
+)
+
+const (
+	tagRef = "refs/tags/"
+)
+
+// TagInfo describes a tag stored in the repository.
+type TagInfo struct {
+	// Name is the short name of the tag (e.g. "v1.4.0").
+	Name string
+	// Commit is the OID of the commit the tag points to.
+	Commit string
+	// Message is the message of annotated tags. It is empty for lightweight tags.
+	Message string
+	// Annotated is true if the tag is stored as a Git tag object.
+	Annotated bool
+}
+
+// Tag creates an annotated tag with the given name pointing to revision. If revision is empty, HEAD is used.
+// The OID of the created tag object is returned.
+func (p *Project) Tag(name, revision string, tagger Person, message string) (tagOid string, err error) {
+	if len(name) == 0 {
+		return "", ErrEmptyTagName
+	} else if !git.ReferenceIsValidName(tagRef + name) {
+		return "", fmt.Errorf("'%s' is not a valid tag name", name)
+	}
+
+	if len(revision) == 0 {
+		revision = "HEAD"
+	}
+
+	commit, err := p.lookupCommit(revision)
+	if err != nil {
+		return "", err
+	}
+
+	gitTagger := git.Signature(tagger)
+	oid, err := p.repo.Tags.Create(name, commit, &gitTagger, message)
+	if err != nil {
+		return "", fmt.Errorf("failed to create tag: %v", err)
+	}
+	return oid.String(), nil
+}
+
+// Tags returns information about all tags in the repository sorted by name.
+func (p *Project) Tags() ([]TagInfo, error) {
+	var tags []TagInfo
+	err := p.repo.Tags.Foreach(func(name string, id *git.Oid) error {
+		info := TagInfo{
+			Name:   strings.TrimPrefix(name, tagRef),
+			Commit: id.String(),
+		}
+
+		// lightweight tags point directly to commits
+		if tag, err := p.repo.LookupTag(id); err == nil {
+			info.Annotated = true
+			info.Message = tag.Message()
+			info.Commit = tag.TargetId().String()
+		}
+
+		tags = append(tags, info)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list tags: %v", err)
+	}
+
+	sort.Sort(tagsByName(tags))
+	return tags, nil
+}
+
+// RemoteTags returns the names of the tags retrieved from remoteName by FetchAll.
+func (p *Project) RemoteTags(remoteName string) ([]string, error) {
+	prefix := remoteTagRef + remoteName + "/"
+	iter, err := p.repo.NewReferenceIteratorGlob(prefix + "*")
+	if err != nil {
+		return nil, fmt.Errorf("could not list tags for '%s': %v", remoteName, err)
+	}
+	defer iter.Free()
+
+	var tags []string
+	names := iter.Names()
+	for {
+		name, err := names.Next()
+		if git.IsErrorCode(err, git.ErrIterOver) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("could not list tags for '%s': %v", remoteName, err)
+		}
+		tags = append(tags, strings.TrimPrefix(name, prefix))
+	}
+
+	sort.Strings(tags)
+	return tags, nil
+}
+
+type tagsByName []TagInfo
+
+func (t tagsByName) Len() int           { return len(t) }
+func (t tagsByName) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+func (t tagsByName) Less(i, j int) bool { return t[i].Name < t[j].Name }
+
+var (
+	// ErrEmptyTagName is returned when a tag is created without a name.
+	ErrEmptyTagName = errors.New("a tag name must be specified")
+)
@@ -0,0 +1,178 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+)
+
+const (
+	// PublicKeyPrefix identifies the encoding of public keys.
+	PublicKeyPrefix = "nacl:"
+
+	// boxKeySize is the length in bytes of NaCl box public and private keys.
+	boxKeySize = 32
+)
+
+// DefaultIdentityFile is the identity used to decrypt documents when none is configured. The identity is created the
+// first time it is needed to add a recipient.
+var DefaultIdentityFile = "~/.spread-identity"
+
+// PublicKey is a NaCl box (Curve25519) public key that documents can be encrypted for.
+type PublicKey []byte
+
+// ParsePublicKey parses a public key in the form returned by PublicKey.String.
+func ParsePublicKey(in string) (PublicKey, error) {
+	if !strings.HasPrefix(in, PublicKeyPrefix) {
+		return nil, fmt.Errorf("public key must begin with '%s'", PublicKeyPrefix)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(in, PublicKeyPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %v", err)
+	} else if len(data) != boxKeySize {
+		return nil, fmt.Errorf("invalid public key: must be %d bytes", boxKeySize)
+	}
+	return PublicKey(data), nil
+}
+
+// String encodes the key for use in recipient lists.
+func (k PublicKey) String() string {
+	return PublicKeyPrefix + base64.StdEncoding.EncodeToString(k)
+}
+
+// array returns the key in the form used by the box package.
+func (k PublicKey) array() *[boxKeySize]byte {
+	var out [boxKeySize]byte
+	copy(out[:], k)
+	return &out
+}
+
+// Identity is a private key able to decrypt data sealed for its public key.
+type Identity struct {
+	private *[boxKeySize]byte
+	Public  PublicKey
+}
+
+// GenerateIdentity creates a random identity.
+func GenerateIdentity() (*Identity, error) {
+	public, private, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate identity: %v", err)
+	}
+	return &Identity{
+		private: private,
+		Public:  PublicKey(public[:]),
+	}, nil
+}
+
+// LoadIdentity reads the identity at path. The '~' character may be used to denote the home directory. If the identity
+// doesn't exist and create is true, it is generated and written to path.
+func LoadIdentity(path string, create bool) (*Identity, error) {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && create {
+		return createIdentity(path)
+	} else if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no identity at '%s'", path)
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read identity: %v", err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil || len(data) != boxKeySize {
+		return nil, fmt.Errorf("'%s' is not a valid identity", path)
+	}
+
+	var private, public [boxKeySize]byte
+	copy(private[:], data)
+	curve25519.ScalarBaseMult(&public, &private)
+	return &Identity{
+		private: &private,
+		Public:  PublicKey(public[:]),
+	}, nil
+}
+
+func createIdentity(path string) (*Identity, error) {
+	id, err := GenerateIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(id.private[:])
+	if err = ioutil.WriteFile(path, []byte(encoded), 0600); err != nil {
+		return nil, fmt.Errorf("could not write identity: %v", err)
+	}
+	return id, nil
+}
+
+// Seal encrypts plaintext so it can be opened by any of recipients. A NaCl sealed box is created for each recipient.
+// Additional data is sealed along with plaintext and must be given to Open, which refuses data sealed with any other.
+func Seal(plaintext, additional []byte, recipients []PublicKey) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	message := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(additional)+len(plaintext))
+	message = message[:binary.PutUvarint(message, uint64(len(additional)))]
+	message = append(append(message, additional...), plaintext...)
+
+	boxes := make([][]byte, len(recipients))
+	for i, recipient := range recipients {
+		sealed, err := box.SealAnonymous(nil, message, recipient.array(), rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("could not encrypt for %s: %v", recipient, err)
+		}
+		boxes[i] = sealed
+	}
+	return json.Marshal(boxes)
+}
+
+// Open decrypts data sealed for id.
+func Open(sealed, additional []byte, id *Identity) ([]byte, error) {
+	var boxes [][]byte
+	if err := json.Unmarshal(sealed, &boxes); err != nil {
+		return nil, fmt.Errorf("invalid sealed data: %v", err)
+	}
+
+	for _, sealedBox := range boxes {
+		message, ok := box.OpenAnonymous(nil, sealedBox, id.Public.array(), id.private)
+		if !ok {
+			// sealed for another recipient
+			continue
+		}
+
+		length, n := binary.Uvarint(message)
+		if n <= 0 || uint64(len(message)-n) < length || !bytes.Equal(message[n:n+int(length)], additional) {
+			return nil, ErrWrongLocation
+		}
+		return message[n+int(length):], nil
+	}
+	return nil, ErrNotRecipient
+}
+
+var (
+	ErrNoRecipients  = errors.New("no recipients to encrypt for")
+	ErrNotRecipient  = errors.New("not encrypted for this identity")
+	ErrWrongLocation = errors.New("value was encrypted for a different location")
+)
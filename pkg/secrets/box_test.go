@@ -0,0 +1,128 @@
+package secrets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testIdentity(t *testing.T) *Identity {
+	id, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestSealOpen(t *testing.T) {
+	alice, bob, eve := testIdentity(t), testIdentity(t), testIdentity(t)
+	additional := []byte("namespaces/default/secret/db?data.password")
+
+	sealed, err := Seal([]byte("hunter2"), additional, []PublicKey{alice.Public, bob.Public})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []*Identity{alice, bob} {
+		plaintext, err := Open(sealed, additional, id)
+		if err != nil {
+			t.Error(err)
+		} else if string(plaintext) != "hunter2" {
+			t.Errorf("expected 'hunter2', got '%s'", plaintext)
+		}
+	}
+
+	if _, err = Open(sealed, additional, eve); err != ErrNotRecipient {
+		t.Errorf("expected ErrNotRecipient, got %v", err)
+	}
+
+	if _, err = Open(sealed, []byte("namespaces/default/secret/other?data.password"), alice); err == nil {
+		t.Error("opening with different additional data should fail")
+	}
+
+	if _, err = Seal([]byte("hunter2"), nil, nil); err != ErrNoRecipients {
+		t.Errorf("expected ErrNoRecipients, got %v", err)
+	}
+}
+
+func TestLoadIdentity(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "identity")
+	if _, err := LoadIdentity(path, false); err == nil {
+		t.Error("missing identities should only be created if requested")
+	}
+
+	created, err := LoadIdentity(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadIdentity(path, false)
+	if err != nil {
+		t.Fatal(err)
+	} else if loaded.Public.String() != created.Public.String() {
+		t.Error("loaded identity should match the one created")
+	}
+
+	key, err := ParsePublicKey(created.Public.String())
+	if err != nil {
+		t.Fatal(err)
+	} else if key.String() != created.Public.String() {
+		t.Errorf("expected '%s', got '%s'", created.Public, key)
+	}
+
+	for _, in := range []string{"", "nacl:", "nacl:AAAA", created.Public.String()[len(PublicKeyPrefix):]} {
+		if _, err = ParsePublicKey(in); err == nil {
+			t.Errorf("'%s' should not be a valid public key", in)
+		}
+	}
+}
+
+func TestRecipients(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	alice, bob := testIdentity(t), testIdentity(t)
+	path := filepath.Join(dir, RecipientsFileName)
+
+	recipients, err := ReadRecipients(path)
+	if err != nil || len(recipients) != 0 {
+		t.Fatalf("expected no recipients, got %v (err: %v)", recipients, err)
+	}
+
+	if recipients, err = recipients.Add(Recipient{Name: "alice", Key: alice.Public}); err != nil {
+		t.Fatal(err)
+	} else if recipients, err = recipients.Add(Recipient{Name: "bob", Key: bob.Public}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = recipients.Add(Recipient{Name: "alice", Key: testIdentity(t).Public}); err == nil {
+		t.Error("names should be unique")
+	} else if _, err = recipients.Add(Recipient{Name: "carol", Key: bob.Public}); err == nil {
+		t.Error("keys should be unique")
+	}
+
+	if err = recipients.Write(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if recipients, err = ReadRecipients(path); err != nil {
+		t.Fatal(err)
+	} else if len(recipients) != 2 || recipients[1].Name != "bob" || recipients[1].Key.String() != bob.Public.String() {
+		t.Errorf("unexpected recipients: %v", recipients)
+	}
+
+	recipients, removed := recipients.Remove("alice")
+	if !removed || len(recipients.Keys()) != 1 {
+		t.Errorf("expected alice to be removed, got %v", recipients)
+	}
+
+	if err = ioutil.WriteFile(path, []byte("alice\n"), 0644); err != nil {
+		t.Fatal(err)
+	} else if _, err = ReadRecipients(path); err == nil {
+		t.Error("lines without a key should be invalid")
+	}
+}
@@ -0,0 +1,108 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+// EncryptedPrefix marks the values of Secret documents which are encrypted.
+const EncryptedPrefix = "spread-encrypted:"
+
+// EncryptedFields are the fields of Secret objects whose values are encrypted.
+var EncryptedFields = []string{"data", "stringData"}
+
+// IsSecretDocument returns true if doc holds a Kubernetes Secret, determined by its path.
+func IsSecretDocument(doc *pb.Document) bool {
+	info := doc.GetInfo()
+	if info == nil {
+		return false
+	}
+
+	parts := strings.Split(info.Path, "/")
+	return len(parts) == 4 && parts[2] == "secret"
+}
+
+// IsEncrypted returns true if any values of doc are encrypted.
+func IsEncrypted(doc *pb.Document) bool {
+	encrypted := false
+	eachSecretValue(doc, func(field *pb.Field, _ []byte) error {
+		encrypted = encrypted || strings.HasPrefix(field.GetStr(), EncryptedPrefix)
+		return nil
+	})
+	return encrypted
+}
+
+// EncryptDocument encrypts the values of the EncryptedFields of a Secret document for recipients. Values which are
+// already encrypted are left as is. Documents other than Secrets aren't modified.
+func EncryptDocument(doc *pb.Document, recipients []PublicKey) error {
+	return eachSecretValue(doc, func(field *pb.Field, additional []byte) error {
+		if strings.HasPrefix(field.GetStr(), EncryptedPrefix) {
+			return nil
+		}
+
+		sealed, err := Seal([]byte(field.GetStr()), additional, recipients)
+		if err != nil {
+			return err
+		}
+
+		field.Value = &pb.Field_Str{Str: EncryptedPrefix + base64.StdEncoding.EncodeToString(sealed)}
+		return nil
+	})
+}
+
+// DecryptDocument decrypts the encrypted values of doc using id.
+func DecryptDocument(doc *pb.Document, id *Identity) error {
+	return eachSecretValue(doc, func(field *pb.Field, additional []byte) error {
+		if !strings.HasPrefix(field.GetStr(), EncryptedPrefix) {
+			return nil
+		}
+
+		sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(field.GetStr(), EncryptedPrefix))
+		if err != nil {
+			return fmt.Errorf("invalid encrypted value: %v", err)
+		}
+
+		plaintext, err := Open(sealed, additional, id)
+		if err != nil {
+			return fmt.Errorf("could not decrypt %s: %v", additional, err)
+		}
+
+		field.Value = &pb.Field_Str{Str: string(plaintext)}
+		return nil
+	})
+}
+
+// eachSecretValue calls fn with each string value of the EncryptedFields of a Secret document. The additional data
+// passed to fn identifies the value, binding encrypted values to their location.
+func eachSecretValue(doc *pb.Document, fn func(field *pb.Field, additional []byte) error) error {
+	if !IsSecretDocument(doc) {
+		return nil
+	}
+
+	root := doc.GetRoot().GetObject().GetItems()
+	for _, name := range EncryptedFields {
+		items := root[name].GetObject().GetItems()
+
+		keys := make([]string, 0, len(items))
+		for key := range items {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			field := items[key]
+			if _, isStr := field.GetValue().(*pb.Field_Str); !isStr {
+				continue
+			}
+
+			if err := fn(field, []byte(doc.GetInfo().Path+"?"+name+"."+key)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
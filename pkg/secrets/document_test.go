@@ -0,0 +1,97 @@
+package secrets
+
+import (
+	"strings"
+	"testing"
+
+	pb "rsprd.com/spread/pkg/spreadproto"
+)
+
+func secretDoc(path string) *pb.Document {
+	str := func(s string) *pb.Field {
+		return &pb.Field{Value: &pb.Field_Str{Str: s}}
+	}
+	return &pb.Document{
+		Info: &pb.DocumentInfo{Path: path},
+		Root: &pb.Field{Value: &pb.Field_Object{Object: &pb.Object{Items: map[string]*pb.Field{
+			"kind": str("Secret"),
+			"data": {Value: &pb.Field_Object{Object: &pb.Object{Items: map[string]*pb.Field{
+				"password": str("aHVudGVyMg=="),
+				"username": str("YWRtaW4="),
+			}}}},
+		}}}},
+	}
+}
+
+func TestEncryptDocument(t *testing.T) {
+	id := testIdentity(t)
+	doc := secretDoc("namespaces/default/secret/db")
+	data := doc.GetRoot().GetObject().GetItems()["data"].GetObject().GetItems()
+
+	if !IsSecretDocument(doc) || IsEncrypted(doc) {
+		t.Fatal("expected an unencrypted Secret")
+	}
+
+	if err := EncryptDocument(doc, []PublicKey{id.Public}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !IsEncrypted(doc) {
+		t.Error("document should be encrypted")
+	}
+
+	for key, field := range data {
+		if !strings.HasPrefix(field.GetStr(), EncryptedPrefix) {
+			t.Errorf("'%s' should be encrypted, got '%s'", key, field.GetStr())
+		}
+	}
+
+	if kind := doc.GetRoot().GetObject().GetItems()["kind"].GetStr(); kind != "Secret" {
+		t.Errorf("only data should be encrypted, kind is '%s'", kind)
+	}
+
+	// encrypted values are left as is
+	sealed := data["password"].GetStr()
+	if err := EncryptDocument(doc, []PublicKey{id.Public}); err != nil {
+		t.Fatal(err)
+	} else if data["password"].GetStr() != sealed {
+		t.Error("encrypted values should not be encrypted again")
+	}
+
+	if err := DecryptDocument(doc, testIdentity(t)); err == nil {
+		t.Error("decrypting with another identity should fail")
+	}
+
+	if err := DecryptDocument(doc, id); err != nil {
+		t.Fatal(err)
+	} else if data["password"].GetStr() != "aHVudGVyMg==" || data["username"].GetStr() != "YWRtaW4=" {
+		t.Errorf("unexpected decrypted data: %v", data)
+	}
+}
+
+func TestEncryptDocumentMoved(t *testing.T) {
+	id := testIdentity(t)
+	doc := secretDoc("namespaces/default/secret/db")
+	if err := EncryptDocument(doc, []PublicKey{id.Public}); err != nil {
+		t.Fatal(err)
+	}
+
+	// values are bound to their location
+	doc.Info.Path = "namespaces/default/secret/other"
+	if err := DecryptDocument(doc, id); err == nil {
+		t.Error("values moved to another document should not decrypt")
+	}
+}
+
+func TestEncryptDocumentNotSecret(t *testing.T) {
+	doc := secretDoc("namespaces/default/configmap/db")
+	if IsSecretDocument(doc) {
+		t.Fatal("ConfigMaps are not Secrets")
+	}
+
+	if err := EncryptDocument(doc, []PublicKey{testIdentity(t).Public}); err != nil {
+		t.Fatal(err)
+	} else if IsEncrypted(doc) {
+		t.Error("only Secrets should be encrypted")
+	}
+}
@@ -0,0 +1,103 @@
+package secrets
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// RecipientsFileName is the name of the file within a project listing who Secret documents are encrypted for.
+const RecipientsFileName = "recipients"
+
+// Recipient is a named public key that documents are encrypted for.
+type Recipient struct {
+	Name string
+	Key  PublicKey
+}
+
+// Recipients is a list of recipients stored one per line as "<name> <public key>". Blank lines and lines beginning
+// with '#' are ignored.
+type Recipients []Recipient
+
+// ReadRecipients reads the recipients at path. If the file doesn't exist, the list is empty.
+func ReadRecipients(path string) (Recipients, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read recipients: %v", err)
+	}
+
+	var recipients Recipients
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected '<name> <public key>'", path, line)
+		}
+
+		key, err := ParsePublicKey(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		recipients = append(recipients, Recipient{Name: fields[0], Key: key})
+	}
+	return recipients, scanner.Err()
+}
+
+// Write saves the recipients to path.
+func (r Recipients) Write(path string) error {
+	var buf bytes.Buffer
+	for _, recipient := range r {
+		fmt.Fprintf(&buf, "%s %s\n", recipient.Name, recipient.Key)
+	}
+
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("could not write recipients: %v", err)
+	}
+	return nil
+}
+
+// Add returns the recipients with recipient added. An error is returned if the name or key is already used.
+func (r Recipients) Add(recipient Recipient) (Recipients, error) {
+	if len(recipient.Name) == 0 || strings.ContainsAny(recipient.Name, " \t#") {
+		return nil, fmt.Errorf("invalid recipient name '%s'", recipient.Name)
+	}
+
+	for _, existing := range r {
+		if existing.Name == recipient.Name {
+			return nil, fmt.Errorf("recipient '%s' already exists", recipient.Name)
+		} else if string(existing.Key) == string(recipient.Key) {
+			return nil, fmt.Errorf("key is already used by recipient '%s'", existing.Name)
+		}
+	}
+	return append(r, recipient), nil
+}
+
+// Remove returns the recipients without the one named name. False is returned if there is no such recipient.
+func (r Recipients) Remove(name string) (Recipients, bool) {
+	out := make(Recipients, 0, len(r))
+	for _, recipient := range r {
+		if recipient.Name != name {
+			out = append(out, recipient)
+		}
+	}
+	return out, len(out) != len(r)
+}
+
+// Keys returns the public keys of the recipients.
+func (r Recipients) Keys() []PublicKey {
+	keys := make([]PublicKey, len(r))
+	for i, recipient := range r {
+		keys[i] = recipient.Key
+	}
+	return keys
+}
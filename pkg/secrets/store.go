@@ -0,0 +1,197 @@
+// Package secrets keeps secrets out of the Git object store in plaintext. Values of secret parameters are held in a
+// local store, and the data of Secret documents is encrypted for a set of recipients.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	// StoreFileName is the name of the secret store within a project.
+	StoreFileName = "secrets.json"
+
+	// KeySize is the length in bytes of the keys used to encrypt stores.
+	KeySize = 32
+)
+
+// DefaultKeyFile is the key used to encrypt stores when none is configured. The key is created the first time a secret
+// is stored.
+var DefaultKeyFile = "~/.spread-secret.key"
+
+// Store holds secrets encrypted with AES-GCM. Changes are only persisted by Save.
+type Store struct {
+	path   string
+	key    []byte
+	sealed map[string]string
+}
+
+// OpenStore reads the store at path which is encrypted with key. If the file doesn't exist, the store is empty.
+func OpenStore(path string, key []byte) (*Store, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	store := &Store{
+		path:   path,
+		key:    key,
+		sealed: map[string]string{},
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read secret store: %v", err)
+	}
+
+	if err = json.Unmarshal(data, &store.sealed); err != nil {
+		return nil, fmt.Errorf("could not parse secret store '%s': %v", path, err)
+	}
+	return store, nil
+}
+
+// Get returns the decrypted secret name. False is returned if the store doesn't contain it.
+func (s *Store) Get(name string) (string, bool, error) {
+	sealed, ok := s.sealed[name]
+	if !ok {
+		return "", false, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", true, fmt.Errorf("secret '%s' is corrupt: %v", name, err)
+	}
+
+	gcm, err := newGCM(s.key)
+	if err != nil {
+		return "", true, err
+	} else if len(data) < gcm.NonceSize() {
+		return "", true, fmt.Errorf("secret '%s' is corrupt", name)
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return "", true, fmt.Errorf("could not decrypt secret '%s', was it stored with a different key?", name)
+	}
+	return string(plaintext), true, nil
+}
+
+// Set encrypts value and stores it as name, replacing any existing secret.
+func (s *Store) Set(name, value string) error {
+	if len(name) == 0 {
+		return ErrEmptyName
+	}
+
+	gcm, err := newGCM(s.key)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return fmt.Errorf("could not generate nonce: %v", err)
+	}
+
+	// the name is authenticated so secrets can't be swapped
+	sealed := gcm.Seal(nonce, nonce, []byte(value), []byte(name))
+	s.sealed[name] = base64.StdEncoding.EncodeToString(sealed)
+	return nil
+}
+
+// Remove deletes the secret name. False is returned if it didn't exist.
+func (s *Store) Remove(name string) bool {
+	_, ok := s.sealed[name]
+	delete(s.sealed, name)
+	return ok
+}
+
+// Names returns the sorted names of the secrets in the store.
+func (s *Store) Names() []string {
+	names := make([]string, 0, len(s.sealed))
+	for name := range s.sealed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Save writes the store to disk, readable only by the current user.
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s.sealed, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = ioutil.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("could not write secret store: %v", err)
+	}
+	return nil
+}
+
+// LoadKey reads the key at path. The '~' character may be used to denote the home directory. If the key doesn't exist
+// and create is true, a random key is generated and written to path.
+func LoadKey(path string, create bool) ([]byte, error) {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && create {
+		return createKey(path)
+	} else if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no secret key at '%s'", path)
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read secret key: %v", err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(string(encoded))
+	if err != nil || len(key) != KeySize {
+		return nil, fmt.Errorf("'%s': %v", path, ErrInvalidKey)
+	}
+	return key, nil
+}
+
+func createKey(path string) ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("could not generate secret key: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(key)
+	if err := ioutil.WriteFile(path, []byte(encoded), 0600); err != nil {
+		return nil, fmt.Errorf("could not write secret key: %v", err)
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+var (
+	ErrInvalidKey = fmt.Errorf("secret keys must be %d bytes", KeySize)
+	ErrEmptyName  = errors.New("secrets must have a name")
+)
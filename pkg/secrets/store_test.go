@@ -0,0 +1,105 @@
+package secrets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "spread-secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestStore(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	key, err := LoadKey(filepath.Join(dir, "key"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, StoreFileName)
+	store, err := OpenStore(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = store.Set("db-password", "hunter2"); err != nil {
+		t.Fatal(err)
+	} else if err = store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	} else if strings.Contains(string(data), "hunter2") {
+		t.Error("secrets must not be stored in plaintext")
+	}
+
+	// reopen with the key read from disk
+	if key, err = LoadKey(filepath.Join(dir, "key"), false); err != nil {
+		t.Fatal(err)
+	} else if store, err = OpenStore(path, key); err != nil {
+		t.Fatal(err)
+	}
+
+	val, ok, err := store.Get("db-password")
+	if err != nil || !ok || val != "hunter2" {
+		t.Errorf("expected 'hunter2', got '%s' (ok: %v, err: %v)", val, ok, err)
+	}
+
+	if _, ok, _ = store.Get("missing"); ok {
+		t.Error("missing secrets should not be found")
+	}
+
+	if names := store.Names(); len(names) != 1 || names[0] != "db-password" {
+		t.Errorf("unexpected names: %v", names)
+	}
+
+	if !store.Remove("db-password") || store.Remove("db-password") {
+		t.Error("secrets should only be removed once")
+	}
+}
+
+func TestStoreWrongKey(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, StoreFileName)
+	store, err := OpenStore(path, make([]byte, KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = store.Set("token", "abc"); err != nil {
+		t.Fatal(err)
+	} else if err = store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	other := make([]byte, KeySize)
+	other[0] = 1
+	if store, err = OpenStore(path, other); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err = store.Get("token"); err == nil {
+		t.Error("decrypting with the wrong key should fail")
+	}
+
+	if _, err = OpenStore(path, []byte("short")); err == nil {
+		t.Error("keys of the wrong size should be rejected")
+	}
+
+	if _, err = LoadKey(filepath.Join(dir, "missing"), false); err == nil {
+		t.Error("missing keys should only be created if requested")
+	}
+}
@@ -34,6 +34,49 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 const _ = proto.ProtoPackageIsVersion1
 
+// ParameterType is the kind of value accepted by a parameter.
+type ParameterType int32
+
+const (
+	ParameterType_ANY      ParameterType = 0
+	ParameterType_STRING   ParameterType = 1
+	ParameterType_NUMBER   ParameterType = 2
+	ParameterType_BOOL     ParameterType = 3
+	ParameterType_ENUM     ParameterType = 4
+	ParameterType_PORT     ParameterType = 5
+	ParameterType_DURATION ParameterType = 6
+	ParameterType_IMAGE    ParameterType = 7
+	ParameterType_SECRET   ParameterType = 8
+)
+
+var ParameterType_name = map[int32]string{
+	0: "ANY",
+	1: "STRING",
+	2: "NUMBER",
+	3: "BOOL",
+	4: "ENUM",
+	5: "PORT",
+	6: "DURATION",
+	7: "IMAGE",
+	8: "SECRET",
+}
+var ParameterType_value = map[string]int32{
+	"ANY":      0,
+	"STRING":   1,
+	"NUMBER":   2,
+	"BOOL":     3,
+	"ENUM":     4,
+	"PORT":     5,
+	"DURATION": 6,
+	"IMAGE":    7,
+	"SECRET":   8,
+}
+
+func (x ParameterType) String() string {
+	return proto.EnumName(ParameterType_name, int32(x))
+}
+func (ParameterType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
 // Field represents a field of an object.
 type Field struct {
 	Key string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
@@ -395,6 +438,17 @@ type Parameter struct {
 	Pattern string `protobuf:"bytes,3,opt,name=pattern" json:"pattern,omitempty"`
 	// Default is the value a parameter will take if no argument is given for it. Parameters without defaults require args.
 	Default *Argument `protobuf:"bytes,4,opt,name=default" json:"default,omitempty"`
+	// Type restricts the arguments accepted by the parameter. Untyped parameters accept any argument.
+	Type ParameterType `protobuf:"varint,5,opt,name=type,enum=spread.ParameterType" json:"type,omitempty"`
+	// Regex must match the string form of arguments.
+	Regex string `protobuf:"bytes,6,opt,name=regex" json:"regex,omitempty"`
+	// Min and Max bound numeric arguments and the length of string arguments. Bounds are only checked if set.
+	Min *Argument `protobuf:"bytes,7,opt,name=min" json:"min,omitempty"`
+	Max *Argument `protobuf:"bytes,8,opt,name=max" json:"max,omitempty"`
+	// Values are the choices of an ENUM parameter.
+	Values []string `protobuf:"bytes,9,rep,name=values" json:"values,omitempty"`
+	// Source computes the argument of the parameter instead of prompting for it, in the form "<kind>:<target>".
+	Source string `protobuf:"bytes,10,opt,name=source" json:"source,omitempty"`
 }
 
 func (m *Parameter) Reset()                    { *m = Parameter{} }
@@ -409,6 +463,20 @@ func (m *Parameter) GetDefault() *Argument {
 	return nil
 }
 
+func (m *Parameter) GetMin() *Argument {
+	if m != nil {
+		return m.Min
+	}
+	return nil
+}
+
+func (m *Parameter) GetMax() *Argument {
+	if m != nil {
+		return m.Max
+	}
+	return nil
+}
+
 // Argument contains an argument to fulfill a parameter.
 type Argument struct {
 	// Types that are valid to be assigned to Value:
@@ -562,38 +630,48 @@ func init() {
 	proto.RegisterType((*DocumentInfo)(nil), "spread.DocumentInfo")
 	proto.RegisterType((*Parameter)(nil), "spread.Parameter")
 	proto.RegisterType((*Argument)(nil), "spread.Argument")
+	proto.RegisterEnum("spread.ParameterType", ParameterType_name, ParameterType_value)
 }
 
 var fileDescriptor0 = []byte{
-	// 471 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x53, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0xc5, 0xf1, 0x67, 0xc6, 0x49, 0x49, 0x0d, 0x07, 0x03, 0x11, 0x2a, 0x96, 0x90, 0x7a, 0xca,
-	0x81, 0x5e, 0x10, 0x9c, 0xa8, 0xf8, 0x8a, 0x84, 0x00, 0xc1, 0x09, 0x24, 0x0e, 0x9b, 0x64, 0x92,
-	0xba, 0xb1, 0xbd, 0xd6, 0x7a, 0x53, 0x29, 0xbf, 0x93, 0x3f, 0xc4, 0xec, 0xd8, 0xdb, 0xc4, 0xb4,
-	0xa7, 0x78, 0xe7, 0xbd, 0x99, 0x37, 0xf3, 0x66, 0x02, 0x23, 0xb9, 0xb8, 0xc6, 0xa5, 0x9e, 0xd5,
-	0x4a, 0x6a, 0x99, 0x04, 0x4d, 0xad, 0x50, 0xac, 0xb2, 0xbf, 0x0e, 0xf8, 0x1f, 0x73, 0x2c, 0x56,
-	0x49, 0x0c, 0xee, 0x16, 0xf7, 0xa9, 0x73, 0xe6, 0x9c, 0x0f, 0x93, 0x09, 0x04, 0xd5, 0xae, 0x5c,
-	0xa0, 0x4a, 0x07, 0xf4, 0x76, 0x3e, 0x3f, 0x48, 0xc6, 0xe0, 0x36, 0x5a, 0xa5, 0xae, 0x81, 0xe9,
-	0x79, 0x0a, 0xe1, 0x42, 0xca, 0x02, 0x45, 0x95, 0x7a, 0x14, 0x8a, 0x28, 0x74, 0x06, 0x41, 0x2b,
-	0x91, 0xfa, 0x14, 0x89, 0x5f, 0x9d, 0xcc, 0x5a, 0x8d, 0xd9, 0x37, 0x8e, 0x12, 0xe3, 0x39, 0xf8,
-	0x42, 0x29, 0xb1, 0x4f, 0x03, 0x26, 0x8c, 0x2d, 0xe1, 0x9d, 0x09, 0x12, 0x3e, 0x05, 0xaf, 0xc8,
-	0xab, 0x6d, 0x1a, 0x32, 0x3c, 0xb2, 0xf0, 0x17, 0x8a, 0x71, 0x7d, 0xbf, 0x16, 0x4a, 0x94, 0x69,
-	0xc4, 0xf0, 0xa9, 0x85, 0xbf, 0x9b, 0x20, 0x6a, 0x54, 0x97, 0x21, 0xf8, 0x37, 0xa2, 0xd8, 0x61,
-	0x26, 0x21, 0x68, 0x45, 0x93, 0x73, 0xf0, 0x73, 0x8d, 0x65, 0x43, 0x73, 0xb9, 0x94, 0xf4, 0xa4,
-	0xdf, 0xd3, 0x6c, 0x6e, 0xb0, 0x0f, 0x95, 0x56, 0xfb, 0xa7, 0x6f, 0x01, 0x0e, 0xaf, 0xbe, 0x1b,
-	0xd3, 0xae, 0x2e, 0x9b, 0x71, 0xd4, 0x37, 0x1b, 0xf7, 0x66, 0xf0, 0xda, 0xc9, 0x5e, 0x82, 0xcf,
-	0x43, 0x18, 0xea, 0xb1, 0x5e, 0x9f, 0x9a, 0x5d, 0x80, 0xfb, 0xf3, 0xc7, 0x3c, 0x79, 0x08, 0xa1,
-	0x56, 0x88, 0x79, 0x73, 0xd5, 0x09, 0x8c, 0xc0, 0xab, 0x85, 0xbe, 0xe2, 0xfa, 0x43, 0xb2, 0xda,
-	0x5f, 0x1b, 0x7a, 0x6b, 0x76, 0x76, 0x0d, 0x9e, 0x71, 0x20, 0x79, 0x04, 0x71, 0x2d, 0x96, 0x5b,
-	0xb1, 0xc1, 0xaf, 0x34, 0x6f, 0x97, 0xf9, 0x0c, 0x02, 0x2d, 0xd4, 0x06, 0x75, 0xd7, 0x5b, 0x6c,
-	0x05, 0x8d, 0xce, 0x04, 0x22, 0x79, 0x83, 0x4a, 0xe5, 0x2b, 0xe4, 0x5a, 0x11, 0x6d, 0xc0, 0x23,
-	0x76, 0x43, 0x3b, 0x33, 0xdd, 0x4d, 0x0e, 0x0b, 0xd8, 0xec, 0x4a, 0xac, 0x74, 0xf6, 0x0b, 0xa2,
-	0xf7, 0x72, 0xc9, 0xdf, 0xa6, 0xa9, 0xea, 0x20, 0x94, 0x81, 0x97, 0x57, 0x6b, 0xd9, 0xc9, 0x3c,
-	0xb6, 0x99, 0x96, 0x3d, 0x27, 0x8c, 0x9a, 0xf1, 0x94, 0x94, 0x9a, 0xb5, 0xee, 0xcc, 0x3e, 0x85,
-	0x51, 0x8f, 0x6c, 0x67, 0xe6, 0xf2, 0xd9, 0x1f, 0x18, 0xde, 0xee, 0xf1, 0x3f, 0xe5, 0x13, 0x08,
-	0xe8, 0x66, 0xcb, 0x5a, 0x77, 0xf6, 0x90, 0x7b, 0x94, 0x48, 0xbc, 0xaa, 0x35, 0x28, 0x79, 0x01,
-	0xe1, 0x0a, 0xd7, 0x62, 0x57, 0x68, 0xbe, 0xc5, 0xfb, 0xe6, 0xfa, 0x04, 0x91, 0xfd, 0x3e, 0xba,
-	0x6d, 0xa7, 0x7f, 0xdb, 0x83, 0xbb, 0xb7, 0xed, 0xb6, 0xb7, 0x7d, 0x7b, 0x59, 0x97, 0xe3, 0xdf,
-	0x71, 0x5b, 0x9b, 0xff, 0x46, 0x8b, 0x80, 0x7f, 0x2e, 0xfe, 0x05, 0x00, 0x00, 0xff, 0xff, 0xc9,
-	0x65, 0x45, 0xb7, 0x5d, 0x03, 0x00, 0x00,
+	// 611 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x03, 0x6d, 0x54, 0x4d, 0x6f, 0xda, 0x40,
+	0x10, 0x8d, 0xf1, 0xf7, 0x00, 0xa9, 0xb3, 0x6d, 0x25, 0xb7, 0xa5, 0x55, 0xea, 0xa8, 0x52, 0xd4,
+	0x03, 0x87, 0xe4, 0x52, 0xb5, 0x27, 0x68, 0x28, 0xb5, 0x94, 0x40, 0x44, 0xc8, 0x21, 0xbd, 0x2d,
+	0xb0, 0x10, 0x07, 0xb0, 0xad, 0xf5, 0x12, 0x85, 0xdf, 0xd9, 0x1f, 0xd2, 0xbf, 0xd0, 0xd9, 0xb5,
+	0x0d, 0xa1, 0xf4, 0xe4, 0xdd, 0x79, 0x6f, 0xe7, 0xcd, 0xbc, 0x9d, 0x35, 0xd4, 0x92, 0xd1, 0x03,
+	0x1b, 0x8b, 0x66, 0xca, 0x13, 0x91, 0x10, 0x2b, 0x4b, 0x39, 0xa3, 0x93, 0xe0, 0xb7, 0x06, 0xe6,
+	0x8f, 0x88, 0x2d, 0x26, 0xa4, 0x0a, 0xfa, 0x9c, 0xad, 0x7d, 0xed, 0x58, 0x3b, 0x75, 0x89, 0x07,
+	0x56, 0xbc, 0x5a, 0x8e, 0x18, 0xf7, 0x2b, 0xb8, 0xd7, 0x7e, 0x1e, 0x90, 0x3a, 0xe8, 0x99, 0xe0,
+	0xbe, 0x2e, 0x61, 0xdc, 0x1e, 0x81, 0x3d, 0x4a, 0x92, 0x05, 0xa3, 0xb1, 0x6f, 0x60, 0xc8, 0xc1,
+	0xd0, 0x31, 0x58, 0xb9, 0x84, 0x6f, 0x62, 0xa4, 0x7a, 0x76, 0xd8, 0xcc, 0x35, 0x9a, 0x7d, 0x15,
+	0x45, 0xc6, 0x07, 0x30, 0x29, 0xe7, 0x74, 0xed, 0x5b, 0x8a, 0x50, 0x2f, 0x09, 0x2d, 0x19, 0x44,
+	0xbc, 0x01, 0xc6, 0x22, 0x8a, 0xe7, 0xbe, 0xad, 0xe0, 0x5a, 0x09, 0x5f, 0x62, 0x4c, 0xe5, 0x37,
+	0x53, 0xca, 0xe9, 0xd2, 0x77, 0x14, 0x7c, 0x54, 0xc2, 0xd7, 0x32, 0xc8, 0x04, 0xe3, 0x6d, 0x1b,
+	0xcc, 0x47, 0xba, 0x58, 0xb1, 0x20, 0x01, 0x2b, 0x17, 0x25, 0xa7, 0x60, 0x46, 0x82, 0x2d, 0x33,
+	0xec, 0x4b, 0xc7, 0x43, 0x6f, 0x76, 0x6b, 0x6a, 0x86, 0x12, 0xeb, 0xc4, 0x82, 0xaf, 0xdf, 0x7e,
+	0x03, 0xd8, 0xee, 0x76, 0xdd, 0x68, 0x14, 0x79, 0x95, 0x19, 0xcf, 0xea, 0x56, 0xc6, 0x7d, 0xad,
+	0x7c, 0xd1, 0x82, 0x4f, 0x60, 0xaa, 0x26, 0x24, 0xf5, 0xb9, 0xde, 0x2e, 0x35, 0x38, 0x07, 0xfd,
+	0x66, 0x10, 0x92, 0x17, 0x60, 0x0b, 0xce, 0x58, 0x94, 0xdd, 0x17, 0x02, 0x35, 0x30, 0x52, 0x2a,
+	0xee, 0x55, 0x7e, 0x17, 0xad, 0x36, 0xa7, 0x92, 0x9e, 0x9b, 0x1d, 0x3c, 0x80, 0x21, 0x1d, 0x20,
+	0x2f, 0xa1, 0x9a, 0xd2, 0xf1, 0x9c, 0xce, 0x58, 0x0f, 0xfb, 0x2d, 0x4e, 0xbe, 0x03, 0x4b, 0x50,
+	0x3e, 0x63, 0xa2, 0xa8, 0xad, 0x5a, 0x0a, 0x4a, 0x1d, 0x0f, 0x9c, 0xe4, 0x91, 0x71, 0x1e, 0x4d,
+	0x98, 0xca, 0xe5, 0xe0, 0x0d, 0x18, 0xc8, 0xce, 0xf0, 0xce, 0x64, 0x75, 0xde, 0xf6, 0x02, 0x66,
+	0xab, 0x25, 0x8b, 0x45, 0x70, 0x07, 0xce, 0x45, 0x32, 0x56, 0x6b, 0x59, 0x54, 0xbc, 0x15, 0x0a,
+	0xc0, 0x88, 0xe2, 0x69, 0x52, 0xc8, 0xbc, 0x2a, 0x4f, 0x96, 0xec, 0x10, 0x31, 0x2c, 0xc6, 0xe0,
+	0x49, 0x22, 0x94, 0xd6, 0x5e, 0xef, 0x0d, 0xa8, 0xed, 0x90, 0xcb, 0x9e, 0x55, 0xfa, 0xe0, 0x8f,
+	0x06, 0xee, 0xe6, 0x22, 0xff, 0x91, 0x3e, 0x04, 0x0b, 0x87, 0x76, 0x99, 0x8a, 0xc2, 0x1f, 0xb4,
+	0x0f, 0x4f, 0x22, 0x2f, 0xce, 0x1d, 0x22, 0x1f, 0xc1, 0x9e, 0xb0, 0x29, 0x5d, 0x2d, 0x84, 0x1a,
+	0xc6, 0xff, 0x34, 0x46, 0x4e, 0xc0, 0x10, 0xeb, 0x94, 0xa9, 0xd1, 0x3c, 0x3c, 0x7b, 0xbd, 0x37,
+	0x3b, 0x43, 0x04, 0xa5, 0xf1, 0x9c, 0xcd, 0xd8, 0x93, 0x9a, 0x4f, 0x97, 0xbc, 0x07, 0x7d, 0x19,
+	0xc5, 0xc5, 0x34, 0xee, 0xa7, 0x94, 0x30, 0x7d, 0x2a, 0xa6, 0x71, 0x1f, 0xc6, 0xaa, 0xd5, 0xd0,
+	0x64, 0xbe, 0x8b, 0x66, 0xab, 0x2e, 0xb2, 0x64, 0xc5, 0xc7, 0xcc, 0x07, 0xd5, 0x71, 0x17, 0x9c,
+	0x0d, 0x77, 0xfb, 0xdc, 0xb4, 0xdd, 0xe7, 0x56, 0xd9, 0x7f, 0x6e, 0x7a, 0xfe, 0xdc, 0x36, 0xc3,
+	0xfe, 0x39, 0x83, 0xfa, 0x6e, 0x1b, 0x36, 0xe8, 0xad, 0xde, 0x9d, 0x77, 0x40, 0x00, 0xac, 0x9b,
+	0xe1, 0x20, 0xec, 0x75, 0x3d, 0x4d, 0xae, 0x7b, 0xb7, 0x57, 0xed, 0xce, 0xc0, 0xab, 0x10, 0x07,
+	0x8c, 0x76, 0xbf, 0x7f, 0xe9, 0xe9, 0x72, 0xd5, 0xc1, 0xb0, 0x67, 0xc8, 0xd5, 0x75, 0x7f, 0x30,
+	0xf4, 0x4c, 0x34, 0xdf, 0xb9, 0xb8, 0x1d, 0xb4, 0x86, 0x61, 0xbf, 0xe7, 0x59, 0xc4, 0x05, 0x33,
+	0xbc, 0x6a, 0x75, 0x3b, 0x9e, 0xad, 0xd2, 0x75, 0xbe, 0x0f, 0x3a, 0x43, 0xcf, 0x69, 0xd7, 0x7f,
+	0x55, 0xf3, 0x86, 0xd5, 0xef, 0x64, 0x64, 0xa9, 0xcf, 0xf9, 0x5f, 0xe6, 0x21, 0xc2, 0x97, 0x65,
+	0x04, 0x00, 0x00,
 }